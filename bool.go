@@ -0,0 +1,69 @@
+package namevalue
+
+import (
+	"strings"
+)
+
+// BoolVocabulary is the set of strings read as true or false
+type BoolVocabulary struct {
+	True  []string
+	False []string
+}
+
+// DefaultBoolVocabulary is the vocabulary used when the name values do not set one
+var DefaultBoolVocabulary = &BoolVocabulary{
+	True:  []string{"true", "t", "yes", "y", "1", "-1", "on", "enabled", "enable"},
+	False: []string{"false", "f", "no", "n", "0", "off", "disabled", "disable"},
+}
+
+// BoolState returns the name value as boolean. The second result reports whether the value
+// is a known true or false value, and the third result returns the existence.
+//
+// Typed booleans are returned as is. Numbers are true if they are 1 or -1 and false if they
+// are 0. Strings are compared case-insensitively with the Booleans vocabulary.
+func (nvp *NameValues) BoolState(name string) (value, known, exists bool) {
+	tmp, exists := nvp.lookup(name, "bool")
+	if !exists {
+		return false, false, exists
+	}
+	value, err := nvp.Booleans.toBool(tmp)
+	return value, err == nil, exists
+}
+
+// Parse returns the boolean value of a string and whether it is in the vocabulary.
+// A nil vocabulary parses with DefaultBoolVocabulary.
+func (bv *BoolVocabulary) Parse(s string) (value, known bool) {
+	if bv == nil {
+		bv = DefaultBoolVocabulary
+	}
+	s = strings.TrimSpace(s)
+	for _, t := range bv.True {
+		if strings.EqualFold(s, t) {
+			return true, true
+		}
+	}
+	for _, f := range bv.False {
+		if strings.EqualFold(s, f) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// toBool converts a value to bool with the vocabulary
+func (bv *BoolVocabulary) toBool(v any) (bool, error) {
+	switch t := v.(type) {
+	case bool:
+		return t, nil
+	case string:
+		if value, known := bv.Parse(t); known {
+			return value, nil
+		}
+		return false, convertError(v, KindBool)
+	}
+	i, err := toInt64(v)
+	if err != nil || i < -1 || i > 1 {
+		return false, convertError(v, KindBool)
+	}
+	return i != 0, nil
+}
@@ -0,0 +1,71 @@
+package namevalue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNVBoolState(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"typed":    true,
+			"upper":    "TRUE",
+			"yes":      "Y",
+			"enabled":  "Enabled",
+			"off":      "off",
+			"minus":    -1,
+			"zero":     0,
+			"two":      2,
+			"maybe":    "maybe",
+			"disabled": " disabled ",
+		},
+	}
+	tests := []struct {
+		name                 string
+		value, known, exists bool
+	}{
+		{"typed", true, true, true},
+		{"upper", true, true, true},
+		{"yes", true, true, true},
+		{"enabled", true, true, true},
+		{"off", false, true, true},
+		{"minus", true, true, true},
+		{"zero", false, true, true},
+		{"two", false, false, true},
+		{"maybe", false, false, true},
+		{"disabled", false, true, true},
+		{"missing", false, false, false},
+	}
+	for _, tt := range tests {
+		value, known, exists := nvs.BoolState(tt.name)
+		if value != tt.value || known != tt.known || exists != tt.exists {
+			t.Errorf("%s: expected %v %v %v, got %v %v %v", tt.name, tt.value, tt.known, tt.exists, value, known, exists)
+		}
+		if value, exists := nvs.Bool(tt.name); value != tt.value || exists != tt.exists {
+			t.Errorf("%s: Bool returned %v %v", tt.name, value, exists)
+		}
+	}
+}
+
+func TestNVBoolVocabulary(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"answer": "Ja",
+			"list":   "ja, nein, JA",
+			"yes":    "yes",
+		},
+		Booleans: &BoolVocabulary{
+			True:  []string{"ja"},
+			False: []string{"nein"},
+		},
+	}
+	if v, known, _ := nvs.BoolState("answer"); !v || !known {
+		t.Errorf("answer: expected a known true")
+	}
+	if _, known, _ := nvs.BoolState("yes"); known {
+		t.Errorf("yes: expected an unknown value with a custom vocabulary")
+	}
+	if v := nvs.Bools("list"); !reflect.DeepEqual(v, []bool{true, false, true}) {
+		t.Errorf("list: unexpected %v", v)
+	}
+}
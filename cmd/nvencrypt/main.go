@@ -0,0 +1,102 @@
+// Command nvencrypt encrypts selected values of a JSON configuration file with a keyring,
+// so that the file can be read with namevalue.NameValues and its Keyring.
+//
+// Usage:
+//
+//	nvencrypt [-key-file file | -key-env name] [-keys names] [-rotate] [-w] file.json
+//
+// The keyring holds entries of a key id and a base64 encoded 32-byte key, such as 2024:q83v...,
+// separated by new lines or commas. The first entry encrypts values. With -rotate, values
+// encrypted with other keys are encrypted again with the first key. The result is written
+// to standard output, or back to the file with -w. Top-level names are written in lower case.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	nv "github.com/stdutil/name-value"
+)
+
+func main() {
+	var (
+		keyFile = flag.String("key-file", "", "file with the keyring")
+		keyEnv  = flag.String("key-env", "NAMEVALUE_KEYRING", "environment variable with the keyring, if there is no key file")
+		keys    = flag.String("keys", "", "comma-separated names, paths or JSON pointers of the values to encrypt")
+		rotate  = flag.Bool("rotate", false, "encrypt values encrypted with an older key again with the first key")
+		write   = flag.Bool("w", false, "write the result to the file instead of standard output")
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: nvencrypt [flags] file.json")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *keyFile, *keyEnv, *keys, *rotate, *write); err != nil {
+		fmt.Fprintln(os.Stderr, "nvencrypt:", err)
+		os.Exit(1)
+	}
+}
+
+func run(path, keyFile, keyEnv, keys string, rotate, write bool) error {
+	var (
+		kr  *nv.Keyring
+		err error
+	)
+	if keyFile != "" {
+		kr, err = nv.LoadKeyring(keyFile)
+	} else {
+		kr, err = nv.KeyringFromEnv(keyEnv)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	nvs, err := nv.FromJSON(data)
+	if err != nil {
+		return err
+	}
+	nvs.Keyring = kr
+	var names []string
+	for _, n := range strings.Split(keys, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	if err := nvs.Encrypt(names...); err != nil {
+		return err
+	}
+	if rotate {
+		if _, err := nvs.RotateKeys(); err != nil {
+			return err
+		}
+	}
+	out, err := nvs.ToJSON()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, out, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	if write {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, buf.Bytes(), fi.Mode().Perm())
+	}
+	_, err = os.Stdout.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,265 @@
+package namevalue
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+// ErrInvalidType is returned when a value cannot be converted to the requested type
+var ErrInvalidType = errors.New("invalid type")
+
+// Kind is the type a value is converted to
+type Kind int
+
+// Value kinds
+const (
+	KindAny Kind = iota
+	KindString
+	KindInt
+	KindInt64
+	KindFloat64
+	KindBool
+	KindDecimal
+	KindTime
+	KindDuration
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt:
+		return "int"
+	case KindInt64:
+		return "int64"
+	case KindFloat64:
+		return "float64"
+	case KindBool:
+		return "bool"
+	case KindDecimal:
+		return "decimal"
+	case KindTime:
+		return "time"
+	case KindDuration:
+		return "duration"
+	}
+	return "any"
+}
+
+// convert converts a value to the kind. Times without a time zone are read in loc.
+func convert(k Kind, v any, loc *time.Location) (any, error) {
+	switch k {
+	case KindString:
+		return toString(v)
+	case KindInt:
+		return toInt(v)
+	case KindInt64:
+		return toInt64(v)
+	case KindFloat64:
+		return toFloat64(v)
+	case KindBool:
+		return toBool(v)
+	case KindDecimal:
+		return toDecimal(v)
+	case KindTime:
+		return toTime(v, nil, loc)
+	case KindDuration:
+		return toDuration(v)
+	}
+	return v, nil
+}
+
+func convertError(v any, k Kind) error {
+	return fmt.Errorf("%w: cannot convert %T to %s", ErrInvalidType, v, k)
+}
+
+// toString converts a scalar value to string
+func toString(v any) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case time.Time:
+		return t.Format(time.RFC3339Nano), nil
+	case nil, []any, map[string]any:
+		return "", convertError(v, KindString)
+	}
+	return anyToStr(v), nil
+}
+
+// toInt64 converts a value to int64 without losing information
+func toInt64(v any) (int64, error) {
+	switch t := v.(type) {
+	case int:
+		return int64(t), nil
+	case int8:
+		return int64(t), nil
+	case int16:
+		return int64(t), nil
+	case int32:
+		return int64(t), nil
+	case int64:
+		return t, nil
+	case uint:
+		return toInt64(uint64(t))
+	case uint8:
+		return int64(t), nil
+	case uint16:
+		return int64(t), nil
+	case uint32:
+		return int64(t), nil
+	case uint64:
+		if t > math.MaxInt64 {
+			return 0, &strconv.NumError{Func: "toInt64", Num: strconv.FormatUint(t, 10), Err: strconv.ErrRange}
+		}
+		return int64(t), nil
+	case float32:
+		return toInt64(float64(t))
+	case float64:
+		if t != math.Trunc(t) || t < math.MinInt64 || t >= math.MaxInt64 {
+			return 0, convertError(v, KindInt64)
+		}
+		return int64(t), nil
+	case ssd.Decimal:
+		if !t.IsInteger() || !t.BigInt().IsInt64() {
+			return 0, convertError(v, KindInt64)
+		}
+		return t.IntPart(), nil
+	case string:
+		return toInteger[int64](t)
+	}
+	return 0, convertError(v, KindInt64)
+}
+
+// toInt converts a value to int without losing information
+func toInt(v any) (int, error) {
+	return toInteger[int](v)
+}
+
+// toFloat64 converts a numeric value to float64
+func toFloat64(v any) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case float32:
+		return float64(t), nil
+	case ssd.Decimal:
+		return t.InexactFloat64(), nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(t), 64)
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, convertError(v, KindFloat64)
+	}
+	return float64(i), nil
+}
+
+// toBool converts a value to bool with the default vocabulary
+func toBool(v any) (bool, error) {
+	return DefaultBoolVocabulary.toBool(v)
+}
+
+// toDecimal converts a value to a decimal. Strings may contain grouping commas and spaces.
+func toDecimal(v any) (ssd.Decimal, error) {
+	switch t := v.(type) {
+	case ssd.Decimal:
+		return t, nil
+	case string:
+		t = strings.ReplaceAll(t, ",", "")
+		t = strings.ReplaceAll(t, " ", "")
+		return ssd.NewFromString(t)
+	case float32:
+		return ssd.NewFromFloat32(t), nil
+	case float64:
+		return ssd.NewFromFloat(t), nil
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return ssd.Decimal{}, convertError(v, KindDecimal)
+	}
+	return ssd.NewFromInt(i), nil
+}
+
+// convertTo converts a value to T with the number format, boolean vocabulary and
+// location of the name values. Types without a conversion rule must match T exactly.
+func convertTo[T any](nvp *NameValues, v any) (T, error) {
+	var (
+		zero T
+		r    any
+		err  error
+	)
+	v = deref(v)
+	switch any(zero).(type) {
+	case string:
+		r, err = toString(v)
+	case bool:
+		r, err = nvp.Booleans.toBool(v)
+	case int:
+		r, err = toIntegerIn[int](nvp, v)
+	case int8:
+		r, err = toIntegerIn[int8](nvp, v)
+	case int16:
+		r, err = toIntegerIn[int16](nvp, v)
+	case int32:
+		r, err = toIntegerIn[int32](nvp, v)
+	case int64:
+		r, err = toIntegerIn[int64](nvp, v)
+	case uint:
+		r, err = toIntegerIn[uint](nvp, v)
+	case uint8:
+		r, err = toIntegerIn[uint8](nvp, v)
+	case uint16:
+		r, err = toIntegerIn[uint16](nvp, v)
+	case uint32:
+		r, err = toIntegerIn[uint32](nvp, v)
+	case uint64:
+		r, err = toIntegerIn[uint64](nvp, v)
+	case uintptr:
+		r, err = toIntegerIn[uintptr](nvp, v)
+	case float32:
+		r, err = toFloatIn[float32](nvp, v)
+	case float64:
+		r, err = toFloatIn[float64](nvp, v)
+	case ssd.Decimal:
+		r, err = toDecimalIn(nvp, v)
+	case time.Time:
+		r, err = toTime(v, nil, nvp.location())
+	case time.Duration:
+		r, err = toDuration(v)
+	default:
+		if t, ok := v.(T); ok {
+			return t, nil
+		}
+		return zero, fmt.Errorf("%w: cannot convert %T to %T", ErrInvalidType, v, zero)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return r.(T), nil
+}
+
+// deref dereferences pointers. Nil pointers are returned as nil.
+func deref(v any) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer {
+		return v
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	return rv.Interface()
+}
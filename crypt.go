@@ -0,0 +1,330 @@
+package namevalue
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Encryption errors
+var (
+	ErrUnknownKey = errors.New("unknown encryption key")
+	ErrDecrypt    = errors.New("cannot decrypt value")
+)
+
+const (
+	encPrefix = "ENC[AES256_GCM,"
+	encSuffix = "]"
+)
+
+// Keyring holds the AES-256 keys used to encrypt and decrypt values.
+// Values are encrypted with the primary key and decrypted with the key they name,
+// so older keys can be kept for decryption after a new primary key is added.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring from 32-byte keys by key id. The primary key encrypts new values.
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	kr := &Keyring{
+		primary: primary,
+		keys:    make(map[string]cipher.AEAD, len(keys)),
+	}
+	for id, key := range keys {
+		if id == "" || strings.ContainsAny(id, ",]") {
+			return nil, fmt.Errorf("namevalue: invalid key id %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("namevalue: key %s must be 32 bytes, not %d", id, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if kr.keys[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := kr.keys[primary]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, primary)
+	}
+	return kr, nil
+}
+
+// ParseKeyring creates a keyring from entries of a key id and a base64 encoded key, such as
+// 2024:q83v... Entries are separated by new lines or commas, and lines starting with # are
+// ignored. The first entry is the primary key, so a key is rotated by adding a new first entry.
+func ParseKeyring(s string) (*Keyring, error) {
+	var primary string
+	keys := make(map[string][]byte)
+	for _, line := range strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == ',' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, enc, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, errors.New("namevalue: key entry must be id:base64key")
+		}
+		id = strings.TrimSpace(id)
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(enc))
+		if err != nil {
+			return nil, fmt.Errorf("namevalue: key %s: %w", id, err)
+		}
+		if primary == "" {
+			primary = id
+		}
+		keys[id] = key
+	}
+	if primary == "" {
+		return nil, errors.New("namevalue: no keys")
+	}
+	return NewKeyring(primary, keys)
+}
+
+// LoadKeyring reads a keyring in the format of ParseKeyring from a file
+func LoadKeyring(path string) (*Keyring, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyring(string(b))
+}
+
+// KeyringFromEnv reads a keyring in the format of ParseKeyring from an environment variable
+func KeyringFromEnv(name string) (*Keyring, error) {
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("namevalue: environment variable %s is not set", name)
+	}
+	return ParseKeyring(s)
+}
+
+// Encrypt encrypts a value with the primary key as ENC[AES256_GCM,id,data].
+// The value is encoded as JSON before it is encrypted, so maps, slices and numbers
+// keep their structure and precision. Secrets in the value are revealed.
+// Values that are already encrypted are returned unchanged.
+func (kr *Keyring) Encrypt(v any) (string, error) {
+	v = revealAll(v)
+	if s, ok := v.(string); ok && IsEncrypted(s) {
+		return s, nil
+	}
+	plain, err := json.Marshal(toPrecise(v))
+	if err != nil {
+		return "", fmt.Errorf("namevalue: cannot encrypt %T: %w", v, err)
+	}
+	return kr.seal(plain)
+}
+
+// Decrypt decrypts a value encrypted with Encrypt.
+// Numbers are decoded as int when they fit, otherwise as shopspring.Decimal.
+// It returns an error wrapping ErrUnknownKey if the keyring does not have its key,
+// or ErrDecrypt if the value is malformed or was changed.
+func (kr *Keyring) Decrypt(s string) (any, error) {
+	plain, err := kr.open(s)
+	if err != nil {
+		return nil, err
+	}
+	v, err := decodePreciseValue(plain)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecrypt, err)
+	}
+	return v, nil
+}
+
+// Rotate re-encrypts a value with the primary key if it was encrypted with another key
+func (kr *Keyring) Rotate(s string) (string, error) {
+	if id, _, ok := parseEncrypted(s); !ok || id == kr.primary {
+		return s, nil
+	}
+	plain, err := kr.open(s)
+	if err != nil {
+		return "", err
+	}
+	return kr.seal(plain)
+}
+
+// seal encrypts plain text with the primary key
+func (kr *Keyring) seal(plain []byte) (string, error) {
+	aead := kr.keys[kr.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	data := aead.Seal(nonce, nonce, plain, []byte(kr.primary))
+	return encPrefix + kr.primary + "," + base64.StdEncoding.EncodeToString(data) + encSuffix, nil
+}
+
+// open decrypts an encrypted value to its plain text
+func (kr *Keyring) open(s string) ([]byte, error) {
+	id, data, ok := parseEncrypted(s)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an encrypted value", ErrDecrypt)
+	}
+	aead, ok := kr.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// IsEncrypted checks if a string has the form ENC[AES256_GCM,id,data]
+func IsEncrypted(s string) bool {
+	_, _, ok := parseEncrypted(s)
+	return ok
+}
+
+// parseEncrypted splits an encrypted value into its key id and data
+func parseEncrypted(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, encPrefix) || !strings.HasSuffix(s, encSuffix) {
+		return "", "", false
+	}
+	id, data, ok := strings.Cut(s[len(encPrefix):len(s)-len(encSuffix)], ",")
+	return id, data, ok && id != "" && data != ""
+}
+
+// decryptValue returns an encrypted string decrypted as a Secret. Other values are returned unchanged.
+func (kr *Keyring) decryptValue(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok || !IsEncrypted(s) {
+		return v, nil
+	}
+	plain, err := kr.Decrypt(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewSecret(plain), nil
+}
+
+// Encrypt encrypts the values of names in place with the Keyring of the name values.
+// Names may be paths or JSON pointers. Names that do not exist are ignored.
+func (nvp *NameValues) Encrypt(names ...string) error {
+	if nvp.Keyring == nil {
+		return errors.New("namevalue: no keyring")
+	}
+	for _, n := range names {
+		v, exists := nvp.find(n)
+		if !exists || v == nil {
+			continue
+		}
+		enc, err := nvp.Keyring.Encrypt(v)
+		if err != nil {
+			return err
+		}
+		if err := nvp.Set(n, enc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RotateKeys re-encrypts every encrypted value, including nested ones, that was not
+// encrypted with the primary key of the Keyring. It returns the number of values re-encrypted.
+func (nvp *NameValues) RotateKeys() (int, error) {
+	if nvp.Keyring == nil {
+		return 0, errors.New("namevalue: no keyring")
+	}
+	if !nvp.prepared {
+		nvp.prepare()
+	}
+	n := 0
+	var err error
+	var rotate func(v any) any
+	rotate = func(v any) any {
+		if err != nil {
+			return v
+		}
+		switch t := v.(type) {
+		case string:
+			r, rerr := nvp.Keyring.Rotate(t)
+			if rerr != nil {
+				err = rerr
+				return v
+			}
+			if r != t {
+				n++
+			}
+			return r
+		case map[string]any:
+			for k, e := range t {
+				t[k] = rotate(e)
+			}
+		case []any:
+			for i, e := range t {
+				t[i] = rotate(e)
+			}
+		}
+		return v
+	}
+	for k, v := range nvp.Pair {
+		nvp.Pair[k] = rotate(v)
+	}
+	return n, err
+}
+
+// sealValue encrypts the value written to a name if the name is one of the EncryptNames.
+// Every write path passes its values through sealValue or sealNames.
+func (nvp *NameValues) sealValue(name string, v any) (any, error) {
+	if nvp.Keyring == nil || !nvp.encrypts(name) || v == nil {
+		return v, nil
+	}
+	return nvp.Keyring.Encrypt(v)
+}
+
+// sealNames encrypts the values of the EncryptNames in root that are not encrypted yet
+func (nvp *NameValues) sealNames(root map[string]any) error {
+	if nvp.Keyring == nil {
+		return nil
+	}
+	fold := !nvp.PointerCaseSensitive
+	for _, n := range nvp.EncryptNames {
+		segs, ok := nameSegments(root, n)
+		if !ok || len(segs) == 0 {
+			continue
+		}
+		_, err := walkPointer(root, segs, fold, false, func(c any, seg string) (any, error) {
+			v, exists := pointerChild(c, seg, fold)
+			if !exists {
+				return c, nil
+			}
+			if s, ok := deref(v).(string); ok && IsEncrypted(s) {
+				return c, nil
+			}
+			enc, err := nvp.sealValue(n, v)
+			if err != nil {
+				return nil, err
+			}
+			return setIn(c, seg, enc, fold)
+		})
+		if err != nil && !errors.Is(err, ErrInvalidPointer) {
+			return fmt.Errorf("%s: %w", n, err)
+		}
+	}
+	return nil
+}
+
+// encrypts checks if the value of a name is encrypted when it is set
+func (nvp *NameValues) encrypts(name string) bool {
+	key := pathKey(name)
+	for _, n := range nvp.EncryptNames {
+		if pathKey(n) == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package namevalue
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func TestKeyring(t *testing.T) {
+	kr, err := ParseKeyring("# keys\n2024:" + testKey(1) + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := kr.Encrypt("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enc, "ENC[AES256_GCM,2024,") || !IsEncrypted(enc) {
+		t.Errorf("unexpected encrypted value %s", enc)
+	}
+	if again, _ := kr.Encrypt(enc); again != enc {
+		t.Errorf("expected an encrypted value not to be encrypted again")
+	}
+	if v, err := kr.Decrypt(enc); err != nil || v != "hunter2" {
+		t.Errorf("expected hunter2, got %q %v", v, err)
+	}
+	tampered := enc[:len(enc)-3] + "AA]"
+	if _, err := kr.Decrypt(tampered); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("expected ErrDecrypt, got %v", err)
+	}
+
+	rotated, err := ParseKeyring("2025:" + testKey(2) + ",2024:" + testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := rotated.Rotate(enc)
+	if err != nil || !strings.HasPrefix(r, "ENC[AES256_GCM,2025,") {
+		t.Errorf("unexpected rotated value %s %v", r, err)
+	}
+	if _, err := kr.Decrypt(r); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+
+	for _, bad := range []string{"", "2024", "2024:" + testKey(1)[:10]} {
+		if _, err := ParseKeyring(bad); err == nil {
+			t.Errorf("expected %q to fail", bad)
+		}
+	}
+}
+
+func TestLoadKeyring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte("a:"+testKey(3)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKeyring(path); err != nil {
+		t.Error(err)
+	}
+	t.Setenv("NAMEVALUE_TEST_KEYRING", "b:"+testKey(4))
+	if _, err := KeyringFromEnv("NAMEVALUE_TEST_KEYRING"); err != nil {
+		t.Error(err)
+	}
+	if _, err := KeyringFromEnv("NAMEVALUE_TEST_MISSING"); err == nil {
+		t.Errorf("expected an error for a missing variable")
+	}
+}
+
+func TestEncryptedValues(t *testing.T) {
+	old, _ := ParseKeyring("old:" + testKey(5))
+	kr, _ := ParseKeyring("new:" + testKey(6) + "\nold:" + testKey(5))
+	pin, _ := old.Encrypt(1234)
+
+	nvs := NameValues{
+		Pair: map[string]any{
+			"user": "zaldy",
+			"pin":  pin,
+			"db":   map[string]any{"password": "hunter2"},
+		},
+		Keyring:      kr,
+		EncryptNames: []string{"db.password", "/token"},
+	}
+
+	if v, _ := nvs.Int("pin"); v != 1234 {
+		t.Errorf("expected 1234, got %d", v)
+	}
+	if v, _ := nvs.Plain("pin"); fmt.Sprint(v) != Redacted {
+		t.Errorf("expected a decrypted value to be a secret, got %v", v)
+	}
+
+	if err := nvs.Encrypt("db.password"); err != nil {
+		t.Fatal(err)
+	}
+	if v := nvs.Pair["db"].(map[string]any)["password"].(string); !IsEncrypted(v) {
+		t.Errorf("expected the password to be encrypted, got %s", v)
+	}
+	if v, _ := nvs.String("/db/password"); v != "hunter2" {
+		t.Errorf("expected hunter2, got %q", v)
+	}
+
+	if err := nvs.Set("token", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := nvs.Set("user", "dave"); err != nil {
+		t.Fatal(err)
+	}
+	if v := nvs.Pair["token"].(string); !IsEncrypted(v) {
+		t.Errorf("expected the token to be encrypted, got %s", v)
+	}
+	if v := nvs.Pair["user"]; v != "dave" {
+		t.Errorf("expected the user not to be encrypted, got %v", v)
+	}
+	if v, _ := nvs.String("token"); v != "abc" {
+		t.Errorf("expected abc, got %q", v)
+	}
+
+	n, err := nvs.RotateKeys()
+	if err != nil || n != 1 {
+		t.Errorf("expected one rotated value, got %d %v", n, err)
+	}
+	if v := nvs.Pair["pin"].(string); !strings.HasPrefix(v, "ENC[AES256_GCM,new,") {
+		t.Errorf("expected the pin to be rotated, got %s", v)
+	}
+
+	nvs.Keyring = nil
+	if v, _ := nvs.String("token"); !IsEncrypted(v) {
+		t.Errorf("expected values to stay encrypted without a keyring, got %s", v)
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{"a.b": 1}}
+	if err := nvs.Set("a.b", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := nvs.Set("db.servers[0].host", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if v := nvs.Pair["a.b"]; v != 2 {
+		t.Errorf("expected the top-level name to be set, got %v", v)
+	}
+	if v, _ := nvs.String("db.servers[0].host"); v != "x" {
+		t.Errorf("expected x, got %q", v)
+	}
+}
+
+func TestEncryptStructuredValues(t *testing.T) {
+	kr, _ := ParseKeyring("k:" + testKey(7))
+	nvs := NameValues{
+		Pair: map[string]any{
+			"db":    map[string]any{"host": "localhost", "pass": "hunter2"},
+			"ratio": 0.123456789,
+			"tags":  []any{"a", 1},
+		},
+		Keyring: kr,
+	}
+	if err := nvs.Encrypt("db", "ratio", "tags"); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []string{"db", "ratio", "tags"} {
+		if s, _ := nvs.Pair[n].(string); !IsEncrypted(s) {
+			t.Errorf("%s: expected the value to be encrypted, got %v", n, nvs.Pair[n])
+		}
+	}
+	sub := nvs.Sub("db")
+	if v, _ := sub.String("pass"); v != "hunter2" {
+		t.Errorf("expected the map to survive encryption, got %q", v)
+	}
+	if v, _ := nvs.Float64("ratio"); v != 0.123456789 {
+		t.Errorf("expected 0.123456789, got %v", v)
+	}
+	if v := nvs.Strings("tags"); !reflect.DeepEqual(v, []string{"a", "1"}) {
+		t.Errorf("unexpected tags %v", v)
+	}
+	if _, err := kr.Encrypt(make(chan int)); err == nil {
+		t.Errorf("expected an error for a value without a JSON encoding")
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	kr, _ := ParseKeyring("k:" + testKey(8))
+	other, _ := ParseKeyring("k:" + testKey(9))
+	enc, _ := kr.Encrypt(1234)
+	id, data, _ := parseEncrypted(enc)
+	raw, _ := base64.StdEncoding.DecodeString(data)
+	raw[len(raw)-1] ^= 1
+	tampered := encPrefix + id + "," + base64.StdEncoding.EncodeToString(raw) + encSuffix
+
+	tests := []struct {
+		name   string
+		ring   *Keyring
+		value  string
+		target error
+	}{
+		{"wrong key", other, enc, ErrDecrypt},
+		{"missing key", func() *Keyring { r, _ := ParseKeyring("x:" + testKey(8)); return r }(), enc, ErrUnknownKey},
+		{"tampered", kr, tampered, ErrDecrypt},
+	}
+	for _, tt := range tests {
+		var got error
+		nvs := NameValues{
+			Pair:           map[string]any{"pin": tt.value},
+			Keyring:        tt.ring,
+			OnDecryptError: func(name string, err error) { got = err },
+		}
+		if v, ok := nvs.String("pin"); !ok || v != "" {
+			t.Errorf("%s: expected no value, got %q", tt.name, v)
+		}
+		if !errors.Is(got, tt.target) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.target, got)
+		}
+		got = nil
+		if v, _ := nvs.Int("pin"); v != 0 || !errors.Is(got, tt.target) {
+			t.Errorf("%s: expected a reported error, got %d %v", tt.name, v, got)
+		}
+	}
+}
+
+func TestEncryptWritePaths(t *testing.T) {
+	kr, _ := ParseKeyring("k:" + testKey(10))
+	newValues := func() NameValues {
+		return NameValues{
+			Pair:         map[string]any{"user": "zaldy"},
+			Keyring:      kr,
+			EncryptNames: []string{"password", "db.pass"},
+		}
+	}
+	encrypted := func(t *testing.T, nvs NameValues, name string) {
+		t.Helper()
+		v, _ := nvs.find(name)
+		if s, _ := v.(string); !IsEncrypted(s) {
+			t.Errorf("expected %s to be encrypted, got %v", name, v)
+		}
+	}
+
+	nvs := newValues()
+	if err := nvs.Apply([]byte(`[{"op": "add", "path": "/password", "value": "hunter2"}, {"op": "add", "path": "/db", "value": {"pass": "x"}}]`)); err != nil {
+		t.Fatal(err)
+	}
+	encrypted(t, nvs, "password")
+	encrypted(t, nvs, "db.pass")
+
+	nvs = newValues()
+	if err := nvs.Apply([]byte(`{"password": "hunter2", "db": {"pass": "x"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	encrypted(t, nvs, "password")
+	encrypted(t, nvs, "db.pass")
+	if v, _ := nvs.String("db.pass"); v != "x" {
+		t.Errorf("expected x, got %q", v)
+	}
+
+	pin, _ := kr.Encrypt("0042")
+	nvs = newValues()
+	nvs.Pair["password"] = "hunter2"
+	nvs.Pair["pin"] = pin
+	s := NewSchema(Field{Name: "password", Type: KindString}, Field{Name: "pin", Type: KindInt})
+	res, err := s.Apply(nvs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted(t, res, "password")
+	encrypted(t, res, "pin")
+	if v, _ := res.Int("pin"); v != 42 {
+		t.Errorf("expected the converted pin 42, got %d", v)
+	}
+}
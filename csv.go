@@ -0,0 +1,177 @@
+package namevalue
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+// CSVOptions controls how ReadCSV parses its input
+type CSVOptions struct {
+	Comma       rune     // Field delimiter. Defaults to a comma
+	Comment     rune     // Lines beginning with this character are ignored
+	TrimSpace   bool     // Trim leading and trailing white space from every field
+	InferTypes  bool     // Convert fields to int, decimal, bool or time.Time where possible. Numbers with leading zeros stay strings
+	TimeLayouts []string // Layouts tried when inferring times. Defaults to CSVTimeLayouts
+}
+
+// CSVTimeLayouts are the layouts tried by ReadCSV when inferring time values
+var CSVTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ReadCSV reads CSV records from r and yields each record as NameValues.
+// The first row is the header and its columns become the names of every following row.
+//
+// Iteration stops after the first error is yielded.
+func ReadCSV(r io.Reader, opts CSVOptions) iter.Seq2[NameValues, error] {
+	return func(yield func(NameValues, error) bool) {
+		cr := csv.NewReader(r)
+		if opts.Comma != 0 {
+			cr.Comma = opts.Comma
+		}
+		cr.Comment = opts.Comment
+		cr.TrimLeadingSpace = opts.TrimSpace
+
+		header, err := cr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			yield(NameValues{}, err)
+			return
+		}
+		for i := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+		}
+		for {
+			rec, err := cr.Read()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(NameValues{}, err)
+				}
+				return
+			}
+			nvs := NameValues{
+				Pair:     make(map[string]any, len(header)),
+				prepared: true,
+			}
+			for i, n := range header {
+				v := rec[i]
+				if opts.TrimSpace {
+					v = strings.TrimSpace(v)
+				}
+				if opts.InferTypes {
+					nvs.Pair[n] = inferType(v, opts.TimeLayouts)
+					continue
+				}
+				nvs.Pair[n] = v
+			}
+			if !yield(nvs, nil) {
+				return
+			}
+		}
+	}
+}
+
+// WriteCSV writes rows to w as CSV with a header row.
+// The columns determine the order of the fields. If no columns are specified,
+// the sorted names of all rows are used.
+func WriteCSV(w io.Writer, rows []NameValues, columns []string) error {
+	if len(columns) == 0 {
+		seen := make(map[string]bool)
+		for i := range rows {
+			for n := range rows[i].Pair {
+				seen[strings.ToLower(n)] = true
+			}
+		}
+		for n := range seen {
+			columns = append(columns, n)
+		}
+		sort.Strings(columns)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	rec := make([]string, len(columns))
+	for i := range rows {
+		for j, c := range columns {
+			v, _ := rows[i].Plain(c)
+			s, err := formatString(v)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", c, err)
+			}
+			rec[j] = s
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// inferType converts a CSV field to the most specific type it can be parsed as
+func inferType(s string, layouts []string) any {
+	if s == "" {
+		return s
+	}
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if hasLeadingZero(s) {
+		return s
+	}
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+	if d, err := ssd.NewFromString(s); err == nil {
+		return d
+	}
+	if len(layouts) == 0 {
+		layouts = CSVTimeLayouts
+	}
+	for _, l := range layouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t
+		}
+	}
+	return s
+}
+
+// hasLeadingZero reports whether s is a number with a leading zero, such as 007 or -01.5.
+// Such values are usually codes whose zeros matter. 0 and 0.5 have no leading zero.
+func hasLeadingZero(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return len(s) > 1 && s[0] == '0' && s[1] >= '0' && s[1] <= '9'
+}
+
+// formatString converts a value to its text representation for CSV and form fields.
+// It returns an error wrapping ErrSecret for secrets.
+func formatString(v any) (string, error) {
+	switch t := deref(v).(type) {
+	case time.Time:
+		return t.Format(time.RFC3339Nano), nil
+	case float32:
+		return strconv.FormatFloat(float64(t), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(t, 'g', -1, 64), nil
+	case Secret:
+		return "", secretError()
+	}
+	return anyToStr(v), nil
+}
@@ -0,0 +1,114 @@
+package namevalue
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+func TestReadCSV(t *testing.T) {
+	in := "Name,Age,Active,Balance,Joined\n" +
+		"Zaldy,48,true,\"1,024.50\",2021-10-17\n" +
+		"Razzie,12,false,10.25,2021-10-18T08:00:00Z\n"
+
+	var rows []NameValues
+	for nvs, err := range ReadCSV(strings.NewReader(in), CSVOptions{InferTypes: true}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, nvs)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+
+	if v := Get[int](rows[0], "age"); v != 48 {
+		t.Errorf("age: expected 48, got %d", v)
+	}
+	if v := Get[bool](rows[0], "ACTIVE"); !v {
+		t.Errorf("active: expected true, got %v", v)
+	}
+	if v, _ := rows[0].Decimal("balance"); !v.Equal(ssd.RequireFromString("1024.50")) {
+		t.Errorf("balance: expected 1024.50, got %s", v)
+	}
+	if v, _ := rows[1].Decimal("balance"); !v.Equal(ssd.RequireFromString("10.25")) {
+		t.Errorf("balance: expected 10.25, got %s", v)
+	}
+	if v, _ := rows[1].Plain("joined"); v != time.Date(2021, 10, 18, 8, 0, 0, 0, time.UTC) {
+		t.Errorf("joined: unexpected %v", v)
+	}
+	if v, _ := rows[1].String("name"); v != "Razzie" {
+		t.Errorf("name: expected Razzie, got %s", v)
+	}
+}
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected any
+	}{
+		{"007", "007"},
+		{"-01", "-01"},
+		{"00.5", "00.5"},
+		{"0", 0},
+		{"-7", -7},
+		{"0.5", ssd.RequireFromString("0.5")},
+	}
+	for _, tt := range tests {
+		v := inferType(tt.in, nil)
+		if d, ok := v.(ssd.Decimal); ok {
+			if !d.Equal(tt.expected.(ssd.Decimal)) {
+				t.Errorf("%q: expected %v, got %v", tt.in, tt.expected, v)
+			}
+			continue
+		}
+		if v != tt.expected {
+			t.Errorf("%q: expected %T %v, got %T %v", tt.in, tt.expected, tt.expected, v, v)
+		}
+	}
+}
+
+func TestReadCSVError(t *testing.T) {
+	in := "a,b\n1,2\n3\n"
+	var (
+		n   int
+		err error
+	)
+	for _, e := range ReadCSV(strings.NewReader(in), CSVOptions{}) {
+		if e != nil {
+			err = e
+			break
+		}
+		n++
+	}
+	if n != 1 || err == nil {
+		t.Errorf("expected 1 row and an error, got %d rows and %v", n, err)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	rows := []NameValues{
+		{Pair: map[string]any{"Name": "Zaldy", "age": 48, "balance": ssd.RequireFromString("1024.5")}},
+		{Pair: map[string]any{"name": "Razzie, Jr.", "age": "12"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, rows, []string{"name", "age", "balance"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "name,age,balance\nZaldy,48,1024.5\n\"Razzie, Jr.\",12,\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteCSV(&buf, rows, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "age,balance,name\n") {
+		t.Errorf("expected sorted header, got %q", buf.String())
+	}
+}
@@ -0,0 +1,470 @@
+package namevalue
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+// ChangeKind is the kind of a Change
+type ChangeKind int
+
+// Change kinds
+const (
+	ChangeAdded ChangeKind = iota
+	ChangeRemoved
+	ChangeModified
+)
+
+// ErrPatchTest is returned by Apply when a test operation of a JSON Patch fails
+var ErrPatchTest = errors.New("patch test failed")
+
+type (
+	// Change is a difference between two name values
+	Change struct {
+		Path string     // JSON pointer of the value
+		Kind ChangeKind // Kind of the change
+		Old  any        // Value before the change. Nil if the value was added
+		New  any        // Value after the change. Nil if the value was removed
+	}
+	// Changes is the list of differences returned by Diff, sorted by path
+	Changes []Change
+	// DiffOptions controls how Diff compares values
+	DiffOptions struct {
+		// Coerce treats values as equal if they convert to the same decimal, bool or string,
+		// such as "48" and 48. Values are compared as bools if either is a bool, such as 1
+		// and true. Otherwise values of different types are different.
+		Coerce bool
+	}
+)
+
+// String returns the name of the change kind
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	}
+	return "modified"
+}
+
+// Diff returns the changes that turn a into b. Nested maps are compared key by key and
+// their changes have paths such as /db/host. Other values, including slices, are
+// compared as a whole. Top-level names are folded to lower case; nested keys are compared exactly.
+func Diff(a, b NameValues, opts DiffOptions) Changes {
+	if !a.prepared {
+		a.prepare()
+	}
+	if !b.prepared {
+		b.prepare()
+	}
+	cmp := compareExact
+	if opts.Coerce {
+		cmp = compareCoerce
+	}
+	var res Changes
+	diffMaps(&res, "", a.Pair, b.Pair, cmp)
+	return res
+}
+
+func diffMaps(res *Changes, path string, a, b map[string]any, cmp comparison) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := path + "/" + escapePointer(k)
+		va, ina := a[k]
+		vb, inb := b[k]
+		switch {
+		case !inb:
+			*res = append(*res, Change{Path: p, Kind: ChangeRemoved, Old: va})
+		case !ina:
+			*res = append(*res, Change{Path: p, Kind: ChangeAdded, New: vb})
+		default:
+			ma, mb := asMap(va), asMap(vb)
+			if ma != nil && mb != nil {
+				diffMaps(res, p, ma, mb, cmp)
+				continue
+			}
+			if !sameValue(va, vb, cmp) {
+				*res = append(*res, Change{Path: p, Kind: ChangeModified, Old: va, New: vb})
+			}
+		}
+	}
+}
+
+// comparison is how sameValue compares values that are not equal as stored
+type comparison int
+
+// Comparisons
+const (
+	compareExact   comparison = iota // Values of different types are different
+	compareNumbers                   // Numbers are compared by value, as in the JSON Patch test operation
+	compareCoerce                    // Values are converted as described by DiffOptions.Coerce
+)
+
+// sameValue compares two values. Slices are compared element by element.
+func sameValue(a, b any, cmp comparison) bool {
+	a, b = deref(a), deref(b)
+	sa, oka := asSlice(a)
+	sb, okb := asSlice(b)
+	if oka && okb {
+		if len(sa) != len(sb) {
+			return false
+		}
+		for i := range sa {
+			if !sameValue(sa[i], sb[i], cmp) {
+				return false
+			}
+		}
+		return true
+	}
+	ma, mb := asMap(a), asMap(b)
+	if ma != nil && mb != nil {
+		if len(ma) != len(mb) {
+			return false
+		}
+		for k, e := range ma {
+			f, ok := mb[k]
+			if !ok || !sameValue(e, f, cmp) {
+				return false
+			}
+		}
+		return true
+	}
+	if equalValue(a, b) {
+		return true
+	}
+	if cmp == compareExact || a == nil || b == nil || oka || okb || ma != nil || mb != nil {
+		return false
+	}
+	if cmp == compareNumbers {
+		da, oka := numberValue(a)
+		db, okb := numberValue(b)
+		return oka && okb && da.Equal(db)
+	}
+	_, boola := a.(bool)
+	_, boolb := b.(bool)
+	if boola || boolb {
+		ba, erra := toBool(a)
+		bb, errb := toBool(b)
+		return erra == nil && errb == nil && ba == bb
+	}
+	if da, err := toDecimal(a); err == nil {
+		db, err := toDecimal(b)
+		return err == nil && da.Equal(db)
+	}
+	sa1, erra := toString(a)
+	sb1, errb := toString(b)
+	return erra == nil && errb == nil && sa1 == sb1
+}
+
+// numberValue returns a number of any type as a decimal. The second result reports
+// whether v is a finite number.
+func numberValue(v any) (ssd.Decimal, bool) {
+	if d, ok := v.(ssd.Decimal); ok {
+		return d, true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ssd.NewFromInt(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return ssd.NewFromBigInt(new(big.Int).SetUint64(rv.Uint()), 0), true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return ssd.Decimal{}, false
+		}
+		if rv.Kind() == reflect.Float32 {
+			return ssd.NewFromFloat32(float32(f)), true
+		}
+		return ssd.NewFromFloat(f), true
+	}
+	return ssd.Decimal{}, false
+}
+
+// JSONPatch encodes the changes as a JSON Patch (RFC 6902)
+func (c Changes) JSONPatch() ([]byte, error) {
+	ops := make([]map[string]any, len(c))
+	for i, ch := range c {
+		switch ch.Kind {
+		case ChangeAdded:
+			ops[i] = map[string]any{"op": "add", "path": ch.Path, "value": toPrecise(ch.New)}
+		case ChangeRemoved:
+			ops[i] = map[string]any{"op": "remove", "path": ch.Path}
+		default:
+			ops[i] = map[string]any{"op": "replace", "path": ch.Path, "value": toPrecise(ch.New)}
+		}
+	}
+	return json.Marshal(ops)
+}
+
+// MergePatch encodes the changes as a JSON Merge Patch (RFC 7386).
+// Removed values are written as null. A merge patch cannot express a value changed to null,
+// which is written as a removal.
+func (c Changes) MergePatch() ([]byte, error) {
+	patch := make(map[string]any)
+	for _, ch := range c {
+		segs, err := parsePointer(ch.Path)
+		if err != nil {
+			return nil, err
+		}
+		if len(segs) == 0 {
+			continue
+		}
+		m := patch
+		for _, seg := range segs[:len(segs)-1] {
+			next, ok := m[seg].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				m[seg] = next
+			}
+			m = next
+		}
+		var v any
+		if ch.Kind != ChangeRemoved {
+			v = toPrecise(ch.New)
+		}
+		m[segs[len(segs)-1]] = v
+	}
+	return json.Marshal(patch)
+}
+
+// Apply applies a JSON Patch (RFC 6902) array or a JSON Merge Patch (RFC 7386) object to the
+// name values. Paths follow the rules of SetPointer for case folding. Test operations
+// compare numbers by value, so a stored int64 48 matches 48.
+//
+// A patch is applied completely or not at all. Values of the EncryptNames are encrypted
+// like Set encrypts them. It returns an error wrapping
+// ErrInvalidPointer if a path cannot be followed, or ErrPatchTest if a test operation fails.
+func (nvp *NameValues) Apply(patch []byte) error {
+	if !nvp.prepared {
+		nvp.prepare()
+	}
+	trim := bytes.TrimSpace(patch)
+	if len(trim) > 0 && trim[0] == '[' {
+		return nvp.applyPatch(trim)
+	}
+	v, err := decodePreciseValue(trim)
+	if err != nil {
+		return err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return errors.New("namevalue: merge patch is not an object")
+	}
+	root, _ := cloneValue(nvp.Pair).(map[string]any)
+	if root == nil {
+		root = make(map[string]any)
+	}
+	for k, e := range m {
+		mergeInto(root, strings.ToLower(k), e, !nvp.PointerCaseSensitive)
+	}
+	if err := nvp.sealNames(root); err != nil {
+		return err
+	}
+	nvp.Pair = root
+	return nil
+}
+
+// mergeInto merges a merge patch value into the key of m
+func mergeInto(m map[string]any, key string, patch any, fold bool) {
+	key = mapKey(m, key, fold)
+	if patch == nil {
+		delete(m, key)
+		return
+	}
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		m[key] = patch
+		return
+	}
+	target := asMap(m[key])
+	if target == nil {
+		target = make(map[string]any)
+	}
+	for k, e := range pm {
+		mergeInto(target, k, e, fold)
+	}
+	m[key] = mapContainer(deref(m[key]), target)
+}
+
+// patchOp is an operation of a JSON Patch
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyPatch applies a JSON Patch to a copy of the values and keeps the copy if all operations succeed
+func (nvp *NameValues) applyPatch(patch []byte) error {
+	var ops []patchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return err
+	}
+	root, _ := cloneValue(nvp.Pair).(map[string]any)
+	if root == nil {
+		root = make(map[string]any)
+	}
+	fold := !nvp.PointerCaseSensitive
+	for i, op := range ops {
+		if err := applyOp(root, op, fold); err != nil {
+			return fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	if err := nvp.sealNames(root); err != nil {
+		return err
+	}
+	nvp.Pair = root
+	return nil
+}
+
+// applyOp applies a single JSON Patch operation
+func applyOp(root map[string]any, op patchOp, fold bool) error {
+	var (
+		v   any
+		err error
+	)
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return fmt.Errorf("namevalue: %s operation without a value", op.Op)
+		}
+		if v, err = decodePreciseValue(op.Value); err != nil {
+			return err
+		}
+	case "move", "copy":
+		from, ok := resolvePointer(root, op.From, fold)
+		if !ok {
+			return fmt.Errorf("%w: %q does not exist", ErrInvalidPointer, op.From)
+		}
+		v = cloneValue(from)
+		if op.Op == "move" {
+			if strings.HasPrefix(op.Path, op.From+"/") {
+				return fmt.Errorf("%w: cannot move %q into itself", ErrInvalidPointer, op.From)
+			}
+			if err := editPatch(root, op.From, fold, removeIn); err != nil {
+				return err
+			}
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("namevalue: unknown patch operation %q", op.Op)
+	}
+	switch op.Op {
+	case "add", "move", "copy":
+		return editPatch(root, op.Path, fold, func(c any, seg string, fold bool) (any, error) {
+			return addIn(c, seg, v, fold)
+		})
+	case "replace":
+		return editPatch(root, op.Path, fold, func(c any, seg string, fold bool) (any, error) {
+			if _, ok := pointerChild(c, seg, fold); !ok {
+				return nil, fmt.Errorf("%w: %q does not exist", ErrInvalidPointer, seg)
+			}
+			return setIn(c, seg, v, fold)
+		})
+	case "remove":
+		return editPatch(root, op.Path, fold, removeIn)
+	}
+	cur, ok := resolvePointer(root, op.Path, fold)
+	if !ok || !sameValue(cur, v, compareNumbers) {
+		return fmt.Errorf("%w: %s", ErrPatchTest, op.Path)
+	}
+	return nil
+}
+
+// editPatch calls edit with the container of the last segment of a path
+func editPatch(root map[string]any, path string, fold bool, edit func(c any, seg string, fold bool) (any, error)) error {
+	segs, err := parsePointer(path)
+	if err != nil {
+		return err
+	}
+	if len(segs) == 0 {
+		return fmt.Errorf("%w: cannot replace all values", ErrInvalidPointer)
+	}
+	segs[0] = strings.ToLower(segs[0])
+	_, err = walkPointer(root, segs, fold, false, func(c any, seg string) (any, error) {
+		return edit(c, seg, fold)
+	})
+	return err
+}
+
+// addIn sets the element of a map named by seg, or inserts it into a slice
+func addIn(c any, seg string, v any, fold bool) (any, error) {
+	s, ok := asSlice(c)
+	if !ok {
+		return setIn(c, seg, v, fold)
+	}
+	i, err := sliceIndex(s, seg, true)
+	if err != nil {
+		return nil, err
+	}
+	s = append(s, nil)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s, nil
+}
+
+// removeIn removes the element of a map or slice named by seg
+func removeIn(c any, seg string, fold bool) (any, error) {
+	if m := asMap(c); m != nil {
+		key := mapKey(m, seg, fold)
+		if _, ok := m[key]; !ok {
+			return nil, fmt.Errorf("%w: %q does not exist", ErrInvalidPointer, seg)
+		}
+		delete(m, key)
+		return mapContainer(c, m), nil
+	}
+	s, ok := asSlice(c)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot remove %q from %T", ErrInvalidPointer, seg, c)
+	}
+	i, err := sliceIndex(s, seg, false)
+	if err != nil {
+		return nil, err
+	}
+	return append(s[:i], s[i+1:]...), nil
+}
+
+// cloneValue returns a deep copy of the maps and slices in a value
+func cloneValue(v any) any {
+	if m := asMap(v); m != nil {
+		c := make(map[string]any, len(m))
+		for k, e := range m {
+			c[k] = cloneValue(e)
+		}
+		return c
+	}
+	if rv := reflect.ValueOf(deref(v)); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		s, _ := asSlice(deref(v))
+		c := make([]any, len(s))
+		for i, e := range s {
+			c[i] = cloneValue(e)
+		}
+		return c
+	}
+	return v
+}
+
+// escapePointer escapes a key for use as a JSON pointer segment
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,152 @@
+package namevalue
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := NameValues{Pair: map[string]any{
+		"Age":  "48",
+		"name": "Zaldy",
+		"db":   map[string]any{"host": "a", "port": 5432},
+		"tags": []any{"x"},
+		"gone": true,
+	}}
+	b := NameValues{Pair: map[string]any{
+		"age":  48,
+		"name": "Zaldy",
+		"db":   map[string]any{"host": "b", "port": 5432, "a/b": 1},
+		"tags": []string{"x"},
+		"new":  nil,
+	}}
+
+	changes := Diff(a, b, DiffOptions{})
+	expected := Changes{
+		{Path: "/age", Kind: ChangeModified, Old: "48", New: 48},
+		{Path: "/db/a~1b", Kind: ChangeAdded, New: 1},
+		{Path: "/db/host", Kind: ChangeModified, Old: "a", New: "b"},
+		{Path: "/gone", Kind: ChangeRemoved, Old: true},
+		{Path: "/new", Kind: ChangeAdded},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %v, got %v", expected, changes)
+	}
+
+	changes = Diff(a, b, DiffOptions{Coerce: true})
+	if len(changes) != 4 || changes[0].Path != "/db/a~1b" {
+		t.Errorf("expected coerced values to be equal, got %v", changes)
+	}
+
+	for _, pair := range [][2]any{{1, true}, {"yes", true}, {0, false}, {"48", 48}} {
+		if !sameValue(pair[0], pair[1], compareCoerce) {
+			t.Errorf("expected %v and %v to be equal when coerced", pair[0], pair[1])
+		}
+	}
+	if sameValue(2, true, compareCoerce) {
+		t.Errorf("expected 2 and true to differ")
+	}
+}
+
+func TestChangesPatch(t *testing.T) {
+	a := NameValues{Pair: map[string]any{"a": 1, "b": map[string]any{"c": "x"}, "d": true}}
+	b := NameValues{Pair: map[string]any{"a": 2, "b": map[string]any{"c": "y"}, "e": "new"}}
+	changes := Diff(a, b, DiffOptions{})
+
+	patch, err := changes.JSONPatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []map[string]any
+	if err := json.Unmarshal(patch, &ops); err != nil || len(ops) != 4 {
+		t.Fatalf("unexpected patch %s", patch)
+	}
+	if ops[2]["op"] != "remove" || ops[2]["path"] != "/d" {
+		t.Errorf("unexpected operation %v", ops[2])
+	}
+
+	merge, err := changes.MergePatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(merge) != `{"a":2,"b":{"c":"y"},"d":null,"e":"new"}` {
+		t.Errorf("unexpected merge patch %s", merge)
+	}
+
+	for _, p := range [][]byte{patch, merge} {
+		c := NameValues{Pair: map[string]any{"a": 1, "b": map[string]any{"c": "x"}, "d": true}}
+		if err := c.Apply(p); err != nil {
+			t.Fatal(err)
+		}
+		if d := Diff(c, b, DiffOptions{}); len(d) != 0 {
+			t.Errorf("expected no differences after applying %s, got %v", p, d)
+		}
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{
+		"items": []any{"a", "c"},
+		"user":  map[string]any{"Name": "Zaldy"},
+	}}
+	patch := `[
+		{"op": "test", "path": "/user/name", "value": "Zaldy"},
+		{"op": "add", "path": "/items/1", "value": "b"},
+		{"op": "add", "path": "/items/-", "value": "d"},
+		{"op": "copy", "from": "/user/name", "path": "/owner"},
+		{"op": "move", "from": "/items/0", "path": "/first"},
+		{"op": "replace", "path": "/user/name", "value": 12.50}
+	]`
+	if err := nvs.Apply([]byte(patch)); err != nil {
+		t.Fatal(err)
+	}
+	if v := nvs.Strings("items"); !reflect.DeepEqual(v, []string{"b", "c", "d"}) {
+		t.Errorf("unexpected items %v", v)
+	}
+	if v, _ := nvs.String("first"); v != "a" {
+		t.Errorf("expected a, got %q", v)
+	}
+	if v, _ := nvs.String("owner"); v != "Zaldy" {
+		t.Errorf("expected Zaldy, got %q", v)
+	}
+	if v, _ := nvs.Decimal("/user/name"); v.String() != "12.5" {
+		t.Errorf("expected 12.5, got %s", v)
+	}
+
+	err := nvs.Apply([]byte(`[{"op": "remove", "path": "/first"}, {"op": "test", "path": "/owner", "value": "x"}]`))
+	if !errors.Is(err, ErrPatchTest) {
+		t.Errorf("expected ErrPatchTest, got %v", err)
+	}
+	if !nvs.Exists("first") {
+		t.Errorf("expected a failed patch not to change the values")
+	}
+	err = nvs.Apply([]byte(`[{"op": "replace", "path": "/missing", "value": 1}]`))
+	if !errors.Is(err, ErrInvalidPointer) {
+		t.Errorf("expected ErrInvalidPointer, got %v", err)
+	}
+
+	row := NameValues{Pair: map[string]any{
+		"id":    int64(48),
+		"ratio": 1.5,
+		"list":  []any{int64(1), float32(0.5)},
+	}}
+	numbers := `[
+		{"op": "test", "path": "/id", "value": 48},
+		{"op": "test", "path": "/ratio", "value": 1.5},
+		{"op": "test", "path": "/ratio", "value": 1.50},
+		{"op": "test", "path": "/list", "value": [1, 0.5]}
+	]`
+	if err := row.Apply([]byte(numbers)); err != nil {
+		t.Errorf("expected numbers to be compared by value, got %v", err)
+	}
+	for _, op := range []string{
+		`[{"op": "test", "path": "/id", "value": 49}]`,
+		`[{"op": "test", "path": "/id", "value": "48"}]`,
+	} {
+		if err := row.Apply([]byte(op)); !errors.Is(err, ErrPatchTest) {
+			t.Errorf("%s: expected ErrPatchTest, got %v", op, err)
+		}
+	}
+}
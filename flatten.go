@@ -0,0 +1,149 @@
+package namevalue
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IndexStyle is how Flatten writes the indexes of slice elements
+type IndexStyle int
+
+// Index styles
+const (
+	IndexDot     IndexStyle = iota // a.0.b
+	IndexBracket                   // a[0].b
+)
+
+// ErrCollision is returned by Unflatten when a key is both a value and the parent of other keys
+var ErrCollision = errors.New("key is both a value and a parent")
+
+// Flatten returns name values with nested maps and slices flattened into keys joined by sep,
+// such as db.primary.host. Slice indexes are written with the index style, which defaults
+// to IndexDot. Empty maps and slices are kept as values. An empty sep defaults to a dot.
+func (nvp *NameValues) Flatten(sep string, style ...IndexStyle) NameValues {
+	if !nvp.prepared {
+		nvp.prepare()
+	}
+	if sep == "" {
+		sep = "."
+	}
+	st := IndexDot
+	if len(style) > 0 {
+		st = style[0]
+	}
+	res := *nvp
+	res.Pair = make(map[string]any, len(nvp.Pair))
+	res.prepared = false
+	res.tracker = nil
+	for k, v := range nvp.Pair {
+		flattenInto(res.Pair, k, v, sep, st)
+	}
+	return res
+}
+
+func flattenInto(dst map[string]any, key string, v any, sep string, st IndexStyle) {
+	if m := asMap(v); m != nil && len(m) > 0 {
+		for k, e := range m {
+			flattenInto(dst, key+sep+k, e, sep, st)
+		}
+		return
+	}
+	rv := reflect.ValueOf(deref(v))
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 && rv.Len() > 0 {
+		for i := 0; i < rv.Len(); i++ {
+			idx := strconv.Itoa(i)
+			if st == IndexBracket {
+				flattenInto(dst, key+"["+idx+"]", rv.Index(i).Interface(), sep, st)
+				continue
+			}
+			flattenInto(dst, key+sep+idx, rv.Index(i).Interface(), sep, st)
+		}
+		return
+	}
+	dst[key] = v
+}
+
+// node is an element of the tree built by Unflatten
+type node struct {
+	children map[string]*node
+	value    any
+	leaf     bool
+}
+
+// Unflatten returns name values with keys joined by sep expanded into nested maps,
+// the reverse of Flatten. Indexes may be written as a.0.b or a[0].b. Numeric segments
+// become slices when they run from zero without gaps, and map keys otherwise.
+// An empty sep defaults to a dot.
+//
+// It returns an error wrapping ErrCollision if a key is both a value and a parent,
+// such as a and a.b, or if two keys name the same value.
+func (nvp *NameValues) Unflatten(sep string) (NameValues, error) {
+	if sep == "" {
+		sep = "."
+	}
+	keys := nvp.Keys()
+	root := &node{children: make(map[string]*node)}
+	for _, k := range keys {
+		segs, ok := parsePath(k, sep)
+		if !ok {
+			segs = []string{k}
+		}
+		cur := root
+		for i, seg := range segs {
+			if cur.leaf {
+				return NameValues{}, fmt.Errorf("%w: %s", ErrCollision, strings.Join(segs[:i], sep))
+			}
+			next, exists := cur.children[seg]
+			if !exists {
+				next = &node{children: make(map[string]*node)}
+				cur.children[seg] = next
+			}
+			cur = next
+		}
+		if cur.leaf || len(cur.children) > 0 {
+			return NameValues{}, fmt.Errorf("%w: %s", ErrCollision, k)
+		}
+		cur.leaf, cur.value = true, nvp.Pair[k]
+	}
+	res := *nvp
+	res.Pair = root.toValue().(map[string]any)
+	res.prepared = false
+	res.tracker = nil
+	return res, nil
+}
+
+// toValue converts a node to a map, a slice or its value
+func (n *node) toValue() any {
+	if n.leaf {
+		return n.value
+	}
+	idx := make([]int, 0, len(n.children))
+	for k := range n.children {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || strconv.Itoa(i) != k {
+			idx = nil
+			break
+		}
+		idx = append(idx, i)
+	}
+	if len(idx) > 0 {
+		sort.Ints(idx)
+		if idx[len(idx)-1] == len(idx)-1 {
+			s := make([]any, len(idx))
+			for k, c := range n.children {
+				i, _ := strconv.Atoi(k)
+				s[i] = c.toValue()
+			}
+			return s
+		}
+	}
+	m := make(map[string]any, len(n.children))
+	for k, c := range n.children {
+		m[k] = c.toValue()
+	}
+	return m
+}
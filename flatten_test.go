@@ -0,0 +1,97 @@
+package namevalue
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"db": map[string]any{
+				"host":  "db1",
+				"ports": []any{5432, 5433},
+			},
+			"servers": []any{map[string]any{"name": "a"}},
+			"empty":   map[string]any{},
+			"name":    "Zaldy",
+		},
+	}
+
+	flat := nvs.Flatten(".")
+	expected := map[string]any{
+		"db.host":        "db1",
+		"db.ports.0":     5432,
+		"db.ports.1":     5433,
+		"servers.0.name": "a",
+		"empty":          map[string]any{},
+		"name":           "Zaldy",
+	}
+	if !reflect.DeepEqual(flat.Pair, expected) {
+		t.Errorf("expected %v, got %v", expected, flat.Pair)
+	}
+
+	bracket := nvs.Flatten("_", IndexBracket)
+	if v, _ := bracket.Plain("db_ports[1]"); v != 5433 {
+		t.Errorf("expected bracket indexes, got %v", bracket.Pair)
+	}
+	if v, _ := bracket.String("servers[0]_name"); v != "a" {
+		t.Errorf("expected bracket indexes, got %v", bracket.Pair)
+	}
+
+	for _, f := range []NameValues{flat, bracket} {
+		sep := "."
+		if _, ok := f.Pair["db_host"]; ok {
+			sep = "_"
+		}
+		back, err := f.Unflatten(sep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(back.Pair, nvs.Pair) {
+			t.Errorf("expected a round trip to %v, got %v", nvs.Pair, back.Pair)
+		}
+	}
+
+	if v := nvs.Flatten(""); !reflect.DeepEqual(v.Pair, expected) {
+		t.Errorf("expected an empty separator to default to a dot, got %v", v.Pair)
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"a.b":      1,
+			"a.c[0]":   "x",
+			"a.c[1]":   "y",
+			"sparse.0": "p",
+			"sparse.2": "q",
+		},
+	}
+	out, err := nvs.Unflatten(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{
+		"a":      map[string]any{"b": 1, "c": []any{"x", "y"}},
+		"sparse": map[string]any{"0": "p", "2": "q"},
+	}
+	if !reflect.DeepEqual(out.Pair, expected) {
+		t.Errorf("expected %v, got %v", expected, out.Pair)
+	}
+	if out, err := nvs.Unflatten(""); err != nil || !reflect.DeepEqual(out.Pair, expected) {
+		t.Errorf("expected an empty separator to default to a dot, got %v, %v", out.Pair, err)
+	}
+
+	for _, pair := range []map[string]any{
+		{"a": 1, "a.b": 2},
+		{"a.b.c": 1, "a.b": 2},
+		{"a[0]": 1, "a.0": 2},
+	} {
+		c := NameValues{Pair: pair}
+		if _, err := c.Unflatten("."); !errors.Is(err, ErrCollision) {
+			t.Errorf("%v: expected ErrCollision, got %v", pair, err)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package namevalue
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormOptions controls how FromValues and ToValues map form values
+type FormOptions struct {
+	// Brackets decodes and encodes PHP and Rails style keys such as user[name] and
+	// user[roles][] as nested maps and slices.
+	Brackets bool
+}
+
+// FromValues creates name values from URL query or form values.
+// Names with a single value are stored as a string and names with several values as []string.
+//
+// With Brackets, user[name]=x is stored as a nested map and user[roles][]=a&user[roles][]=b
+// as a nested slice. The n-th value of a key with [] in the middle, such as items[][name],
+// belongs to the n-th element. Numeric indexes such as items[0][name] also create slices.
+// If a bracketed name has several values, the last one is used.
+func FromValues(values url.Values, opts FormOptions) NameValues {
+	nvs := NameValues{
+		Pair:     make(map[string]any, len(values)),
+		prepared: true,
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		vals := values[k]
+		segs, ok := parseBrackets(k)
+		if !opts.Brackets || !ok || len(segs) == 1 {
+			name := strings.ToLower(k)
+			if len(vals) == 1 {
+				nvs.Pair[name] = vals[0]
+				continue
+			}
+			nvs.Pair[name] = append([]string(nil), vals...)
+			continue
+		}
+		segs[0] = strings.ToLower(segs[0])
+		for i, v := range vals {
+			path := make([]string, len(segs))
+			for j, s := range segs {
+				if s == "" {
+					s = strconv.Itoa(i)
+				}
+				path[j] = s
+			}
+			setPath(nvs.Pair, path, v)
+		}
+	}
+	for k, v := range nvs.Pair {
+		nvs.Pair[k] = indexedToSlices(v)
+	}
+	return nvs
+}
+
+// ToValues converts name values to URL query or form values.
+// Slices are written as repeated values.
+//
+// With Brackets, nested maps are written as user[name], slices as user[roles][] and
+// slices of maps as items[0][name]. Without Brackets, nested keys are joined by dots.
+//
+// It returns an error wrapping ErrSecret if a value is a secret.
+func ToValues(nvs NameValues, opts FormOptions) (url.Values, error) {
+	res := make(url.Values)
+	if !nvs.prepared {
+		nvs.prepare()
+	}
+	for k, v := range nvs.Pair {
+		if err := encodeValue(res, k, v, opts.Brackets); err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+	}
+	return res, nil
+}
+
+func encodeValue(dst url.Values, key string, v any, brackets bool) error {
+	child := func(k string) string {
+		if brackets {
+			return key + "[" + k + "]"
+		}
+		return key + "." + k
+	}
+	v = deref(v)
+	if m := asMap(v); m != nil {
+		for k, e := range m {
+			if err := encodeValue(dst, child(k), e, brackets); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < rv.Len(); i++ {
+			e := deref(rv.Index(i).Interface())
+			erv := reflect.ValueOf(e)
+			nested := asMap(e) != nil || erv.Kind() == reflect.Slice || erv.Kind() == reflect.Array
+			if nested {
+				if err := encodeValue(dst, child(strconv.Itoa(i)), e, brackets); err != nil {
+					return err
+				}
+				continue
+			}
+			s, err := formatString(e)
+			if err != nil {
+				return err
+			}
+			if brackets {
+				dst.Add(key+"[]", s)
+				continue
+			}
+			dst.Add(key, s)
+		}
+		return nil
+	}
+	s, err := formatString(v)
+	if err != nil {
+		return err
+	}
+	dst.Add(key, s)
+	return nil
+}
+
+// parseBrackets splits a key such as user[roles][] into user, roles and an empty segment
+func parseBrackets(key string) ([]string, bool) {
+	name, rest, ok := strings.Cut(key, "[")
+	if !ok {
+		return []string{key}, true
+	}
+	if name == "" {
+		return nil, false
+	}
+	segs := []string{name}
+	for rest != "" {
+		seg, after, ok := strings.Cut(rest, "]")
+		if !ok {
+			return nil, false
+		}
+		segs = append(segs, seg)
+		if after == "" {
+			break
+		}
+		if after[0] != '[' {
+			return nil, false
+		}
+		rest = after[1:]
+	}
+	return segs, true
+}
+
+// setPath sets a value in nested maps, creating the maps on the way.
+// A value in the way of the path is replaced.
+func setPath(m map[string]any, path []string, v any) {
+	for _, seg := range path[:len(path)-1] {
+		next, ok := m[seg].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			m[seg] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = v
+}
+
+// indexedToSlices converts nested maps whose keys are the indexes 0 to n-1 into slices
+func indexedToSlices(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for k, e := range m {
+		m[k] = indexedToSlices(e)
+	}
+	s := make([]any, len(m))
+	for k, e := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != k {
+			return m
+		}
+		s[i] = e
+	}
+	return s
+}
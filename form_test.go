@@ -0,0 +1,103 @@
+package namevalue
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestFromValues(t *testing.T) {
+	q, _ := url.ParseQuery("User[name]=Zaldy&user[roles][]=admin&user[roles][]=dev&a[0][b]=1&a[1][b]=2&page=3&tag=x&tag=y")
+
+	nvs := FromValues(q, FormOptions{Brackets: true})
+	if v, _ := nvs.String("user.name"); v != "Zaldy" {
+		t.Errorf("expected Zaldy, got %q", v)
+	}
+	if v := nvs.Strings("user.roles"); !reflect.DeepEqual(v, []string{"admin", "dev"}) {
+		t.Errorf("unexpected roles %v", v)
+	}
+	if v, _ := nvs.Int("a[1].b"); v != 2 {
+		t.Errorf("expected 2, got %d", v)
+	}
+	if v, _ := nvs.Int("page"); v != 3 {
+		t.Errorf("expected 3, got %d", v)
+	}
+	if v := nvs.Strings("tag"); !reflect.DeepEqual(v, []string{"x", "y"}) {
+		t.Errorf("unexpected tags %v", v)
+	}
+	sub := nvs.Sub("user")
+	if v := sub.Strings("roles"); len(v) != 2 {
+		t.Errorf("unexpected sub roles %v", v)
+	}
+
+	plain := FromValues(q, FormOptions{})
+	if v := plain.Strings("user[roles][]"); !reflect.DeepEqual(v, []string{"admin", "dev"}) {
+		t.Errorf("unexpected plain roles %v", v)
+	}
+}
+
+func TestFromValuesGroupedElements(t *testing.T) {
+	q, _ := url.ParseQuery("items[][name]=a&items[][qty]=1&items[][name]=b&items[][qty]=2&m[7]=x")
+
+	nvs := FromValues(q, FormOptions{Brackets: true})
+	if v, _ := nvs.String("items[1].name"); v != "b" {
+		t.Errorf("expected b, got %q", v)
+	}
+	if v, _ := nvs.Int("items[1].qty"); v != 2 {
+		t.Errorf("expected 2, got %d", v)
+	}
+	if v, _ := nvs.String("m.7"); v != "x" {
+		t.Errorf("expected a sparse index to stay a map key, got %q", v)
+	}
+}
+
+func TestToValues(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{
+		"user": map[string]any{
+			"name":  "Zaldy",
+			"roles": []string{"admin", "dev"},
+		},
+		"items": []any{map[string]any{"name": "a"}},
+		"page":  3,
+	}}
+
+	v, err := ToValues(nvs, FormOptions{Brackets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{
+		"user[name]":     {"Zaldy"},
+		"user[roles][]":  {"admin", "dev"},
+		"items[0][name]": {"a"},
+		"page":           {"3"},
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v, got %v", expected, v)
+	}
+
+	back := FromValues(v, FormOptions{Brackets: true})
+	if r := back.Strings("user.roles"); !reflect.DeepEqual(r, []string{"admin", "dev"}) {
+		t.Errorf("unexpected round trip %v", r)
+	}
+
+	v, err = ToValues(nvs, FormOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := v["user.roles"]; !reflect.DeepEqual(r, []string{"admin", "dev"}) {
+		t.Errorf("unexpected plain roles %v", r)
+	}
+	if r := v.Get("items.0.name"); r != "a" {
+		t.Errorf("expected a, got %q", r)
+	}
+
+	nvs.Pair["password"] = NewSecret("hunter2")
+	if _, err := ToValues(nvs, FormOptions{}); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected ErrSecret, got %v", err)
+	}
+	revealed := nvs.Reveal()
+	if v, err := ToValues(revealed, FormOptions{}); err != nil || v.Get("password") != "hunter2" {
+		t.Errorf("expected the revealed secret, got %v %v", v, err)
+	}
+}
@@ -0,0 +1,300 @@
+package namevalue
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ErrNotFound is returned when a name does not exist
+var ErrNotFound = errors.New("name not found")
+
+// GetInteger gets the value from the collection of NameValues by name as an integer of type T.
+//
+// Unlike the getter methods, it reports why a value could not be read. It returns ErrNotFound
+// if the name does not exist, and a *strconv.NumError wrapping strconv.ErrRange if the value
+// does not fit in T instead of wrapping around.
+func GetInteger[T constraints.Integer](nvs NameValues, name string) (T, error) {
+	tmp, exists := nvs.lookup(name, typeName[T]())
+	if !exists {
+		return 0, ErrNotFound
+	}
+	return toIntegerIn[T](&nvs, tmp)
+}
+
+// GetFloat gets the value from the collection of NameValues by name as a float of type T.
+//
+// It returns ErrNotFound if the name does not exist, and a *strconv.NumError wrapping
+// strconv.ErrRange if the value does not fit in T.
+func GetFloat[T constraints.Float](nvs NameValues, name string) (T, error) {
+	tmp, exists := nvs.lookup(name, typeName[T]())
+	if !exists {
+		return 0, ErrNotFound
+	}
+	return toFloatIn[T](&nvs, tmp)
+}
+
+// Int8 returns the name value as int8. The second result returns the existence.
+func (nvp *NameValues) Int8(name string) (int8, bool) {
+	return getInteger[int8](nvp, name)
+}
+
+// Int16 returns the name value as int16. The second result returns the existence.
+func (nvp *NameValues) Int16(name string) (int16, bool) {
+	return getInteger[int16](nvp, name)
+}
+
+// Int32 returns the name value as int32. The second result returns the existence.
+func (nvp *NameValues) Int32(name string) (int32, bool) {
+	return getInteger[int32](nvp, name)
+}
+
+// Uint returns the name value as uint. The second result returns the existence.
+func (nvp *NameValues) Uint(name string) (uint, bool) {
+	return getInteger[uint](nvp, name)
+}
+
+// Uint8 returns the name value as uint8. The second result returns the existence.
+func (nvp *NameValues) Uint8(name string) (uint8, bool) {
+	return getInteger[uint8](nvp, name)
+}
+
+// Uint16 returns the name value as uint16. The second result returns the existence.
+func (nvp *NameValues) Uint16(name string) (uint16, bool) {
+	return getInteger[uint16](nvp, name)
+}
+
+// Uint32 returns the name value as uint32. The second result returns the existence.
+func (nvp *NameValues) Uint32(name string) (uint32, bool) {
+	return getInteger[uint32](nvp, name)
+}
+
+// Uint64 returns the name value as uint64. The second result returns the existence.
+func (nvp *NameValues) Uint64(name string) (uint64, bool) {
+	return getInteger[uint64](nvp, name)
+}
+
+// Float32 returns the name value as float32. The second result returns the existence.
+func (nvp *NameValues) Float32(name string) (float32, bool) {
+	tmp, exists := nvp.lookup(name, "float32")
+	if !exists {
+		return 0, exists
+	}
+	val, _ := toFloatIn[float32](nvp, tmp)
+	return val, exists
+}
+
+// Int8s returns the values as an int8 array
+// If the name does not exist, this function will return an empty int8 array
+func (nvp *NameValues) Int8s(name string) []int8 {
+	return listOf(nvp, name, integerIn[int8](nvp))
+}
+
+// Int16s returns the values as an int16 array
+// If the name does not exist, this function will return an empty int16 array
+func (nvp *NameValues) Int16s(name string) []int16 {
+	return listOf(nvp, name, integerIn[int16](nvp))
+}
+
+// Int32s returns the values as an int32 array
+// If the name does not exist, this function will return an empty int32 array
+func (nvp *NameValues) Int32s(name string) []int32 {
+	return listOf(nvp, name, integerIn[int32](nvp))
+}
+
+// Uints returns the values as a uint array
+// If the name does not exist, this function will return an empty uint array
+func (nvp *NameValues) Uints(name string) []uint {
+	return listOf(nvp, name, integerIn[uint](nvp))
+}
+
+// Uint8s returns the values as a uint8 array
+// If the name does not exist, this function will return an empty uint8 array
+func (nvp *NameValues) Uint8s(name string) []uint8 {
+	return listOf(nvp, name, integerIn[uint8](nvp))
+}
+
+// Uint16s returns the values as a uint16 array
+// If the name does not exist, this function will return an empty uint16 array
+func (nvp *NameValues) Uint16s(name string) []uint16 {
+	return listOf(nvp, name, integerIn[uint16](nvp))
+}
+
+// Uint32s returns the values as a uint32 array
+// If the name does not exist, this function will return an empty uint32 array
+func (nvp *NameValues) Uint32s(name string) []uint32 {
+	return listOf(nvp, name, integerIn[uint32](nvp))
+}
+
+// Uint64s returns the values as a uint64 array
+// If the name does not exist, this function will return an empty uint64 array
+func (nvp *NameValues) Uint64s(name string) []uint64 {
+	return listOf(nvp, name, integerIn[uint64](nvp))
+}
+
+// Float32s returns the values as a float32 array
+// If the name does not exist, this function will return an empty float32 array
+func (nvp *NameValues) Float32s(name string) []float32 {
+	return listOf(nvp, name, floatIn[float32](nvp))
+}
+
+// PtrInt8 returns the name value as pointer to int8. The second result returns the existence.
+func (nvp *NameValues) PtrInt8(name string) (*int8, bool) {
+	return ptrOf(nvp.Int8(name))
+}
+
+// PtrInt16 returns the name value as pointer to int16. The second result returns the existence.
+func (nvp *NameValues) PtrInt16(name string) (*int16, bool) {
+	return ptrOf(nvp.Int16(name))
+}
+
+// PtrInt32 returns the name value as pointer to int32. The second result returns the existence.
+func (nvp *NameValues) PtrInt32(name string) (*int32, bool) {
+	return ptrOf(nvp.Int32(name))
+}
+
+// PtrUint returns the name value as pointer to uint. The second result returns the existence.
+func (nvp *NameValues) PtrUint(name string) (*uint, bool) {
+	return ptrOf(nvp.Uint(name))
+}
+
+// PtrUint8 returns the name value as pointer to uint8. The second result returns the existence.
+func (nvp *NameValues) PtrUint8(name string) (*uint8, bool) {
+	return ptrOf(nvp.Uint8(name))
+}
+
+// PtrUint16 returns the name value as pointer to uint16. The second result returns the existence.
+func (nvp *NameValues) PtrUint16(name string) (*uint16, bool) {
+	return ptrOf(nvp.Uint16(name))
+}
+
+// PtrUint32 returns the name value as pointer to uint32. The second result returns the existence.
+func (nvp *NameValues) PtrUint32(name string) (*uint32, bool) {
+	return ptrOf(nvp.Uint32(name))
+}
+
+// PtrUint64 returns the name value as pointer to uint64. The second result returns the existence.
+func (nvp *NameValues) PtrUint64(name string) (*uint64, bool) {
+	return ptrOf(nvp.Uint64(name))
+}
+
+// PtrFloat32 returns the name value as pointer to float32. The second result returns the existence.
+func (nvp *NameValues) PtrFloat32(name string) (*float32, bool) {
+	return ptrOf(nvp.Float32(name))
+}
+
+// getInteger gets the name value as an integer, returning zero if it cannot be converted
+func getInteger[T constraints.Integer](nvp *NameValues, name string) (T, bool) {
+	tmp, exists := nvp.lookup(name, typeName[T]())
+	if !exists {
+		return 0, exists
+	}
+	val, _ := toIntegerIn[T](nvp, tmp)
+	return val, exists
+}
+
+// ptrOf returns a pointer to the value if it exists
+func ptrOf[T any](value T, exists bool) (*T, bool) {
+	if !exists {
+		return nil, exists
+	}
+	return &value, exists
+}
+
+// toInteger converts a value to an integer of type T, reporting values that are out of range
+func toInteger[T constraints.Integer](v any) (T, error) {
+	var (
+		neg bool
+		u   uint64
+		num string
+	)
+	switch t := v.(type) {
+	case uint:
+		u = uint64(t)
+	case uint64:
+		u = t
+	case uintptr:
+		u = uint64(t)
+	case string:
+		var err error
+		num = strings.TrimSpace(t)
+		if neg, u, err = parseInteger(num); err != nil {
+			return 0, err
+		}
+	default:
+		i, err := toInt64(v)
+		if err != nil {
+			return 0, err
+		}
+		neg, u = i < 0, uint64(i)
+		if neg {
+			u = uint64(-(i + 1)) + 1
+		}
+	}
+	if num == "" {
+		num = anyToStr(v)
+	}
+	rangeErr := &strconv.NumError{Func: "toInteger", Num: num, Err: strconv.ErrRange}
+	if neg {
+		if u > 1<<63 {
+			return 0, rangeErr
+		}
+		i := -int64(u-1) - 1
+		r := T(i)
+		if r >= 0 || int64(r) != i {
+			return 0, rangeErr
+		}
+		return r, nil
+	}
+	r := T(u)
+	if r < 0 || uint64(r) != u {
+		return 0, rangeErr
+	}
+	return r, nil
+}
+
+// parseInteger parses an integer string into its sign and magnitude.
+// It accepts 0x, 0o and 0b prefixes and _ separators. Leading zeros without
+// a prefix are decimal, not octal.
+func parseInteger(s string) (bool, uint64, error) {
+	neg := false
+	body := s
+	if body != "" && (body[0] == '-' || body[0] == '+') {
+		neg = body[0] == '-'
+		body = body[1:]
+	}
+	if body == "" {
+		return false, 0, &strconv.NumError{Func: "parseInteger", Num: s, Err: strconv.ErrSyntax}
+	}
+	lb := strings.ToLower(body)
+	if !strings.HasPrefix(lb, "0x") && !strings.HasPrefix(lb, "0o") && !strings.HasPrefix(lb, "0b") {
+		body = strings.TrimLeft(body, "0")
+		if body == "" || body[0] == '_' {
+			body = "0" + body
+		}
+	}
+	u, err := strconv.ParseUint(body, 0, 64)
+	if err != nil {
+		if ne, ok := err.(*strconv.NumError); ok {
+			ne.Func, ne.Num = "parseInteger", s
+		}
+		return false, 0, err
+	}
+	return neg && u != 0, u, nil
+}
+
+// toFloat converts a value to a float of type T, reporting values that are out of range
+func toFloat[T constraints.Float](v any) (T, error) {
+	f, err := toFloat64(v)
+	if err != nil {
+		return 0, err
+	}
+	r := T(f)
+	if !math.IsInf(f, 0) && math.IsInf(float64(r), 0) {
+		return 0, &strconv.NumError{Func: "toFloat", Num: anyToStr(v), Err: strconv.ErrRange}
+	}
+	return r, nil
+}
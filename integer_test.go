@@ -0,0 +1,107 @@
+package namevalue
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNVIntegerWidths(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"small":    "127",
+			"overflow": "128",
+			"negative": -1,
+			"hex":      "0xFF",
+			"octal":    "0o17",
+			"binary":   "-0b101",
+			"grouped":  "1_000_000",
+			"leading":  "0042",
+			"typed":    int16(300),
+			"max":      uint64(math.MaxUint64),
+			"float":    3.0,
+			"fraction": 3.5,
+			"empty":    "",
+		},
+	}
+
+	if v, _ := nvs.Int8("small"); v != 127 {
+		t.Errorf("small: expected 127, got %d", v)
+	}
+	if v, exists := nvs.Int8("overflow"); v != 0 || !exists {
+		t.Errorf("overflow: expected 0, got %d", v)
+	}
+	if v, _ := nvs.Uint("negative"); v != 0 {
+		t.Errorf("negative: expected 0, got %d", v)
+	}
+	if v, _ := nvs.Uint8("hex"); v != 255 {
+		t.Errorf("hex: expected 255, got %d", v)
+	}
+	if v, _ := nvs.Int16("octal"); v != 15 {
+		t.Errorf("octal: expected 15, got %d", v)
+	}
+	if v, _ := nvs.Int32("binary"); v != -5 {
+		t.Errorf("binary: expected -5, got %d", v)
+	}
+	if v, _ := nvs.Int("grouped"); v != 1000000 {
+		t.Errorf("grouped: expected 1000000, got %d", v)
+	}
+	if v, _ := nvs.Int64("leading"); v != 42 {
+		t.Errorf("leading: expected 42, got %d", v)
+	}
+	if v, _ := nvs.Int("typed"); v != 300 {
+		t.Errorf("typed: expected 300, got %d", v)
+	}
+	if v, _ := nvs.Uint8("typed"); v != 0 {
+		t.Errorf("typed: expected 0 for uint8, got %d", v)
+	}
+	if v, _ := nvs.Uint64("max"); v != math.MaxUint64 {
+		t.Errorf("max: expected MaxUint64, got %d", v)
+	}
+	if v, _ := nvs.Int64("max"); v != 0 {
+		t.Errorf("max: expected 0 for int64, got %d", v)
+	}
+	if v, _ := nvs.Uint16("float"); v != 3 {
+		t.Errorf("float: expected 3, got %d", v)
+	}
+	if p, _ := nvs.PtrUint32("hex"); p == nil || *p != 255 {
+		t.Errorf("hex: unexpected pointer %v", p)
+	}
+	if s := nvs.Int8s("small"); len(s) != 1 || s[0] != 127 {
+		t.Errorf("small: unexpected slice %v", s)
+	}
+}
+
+func TestGetInteger(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"overflow": "300",
+			"fraction": 3.5,
+			"empty":    "",
+			"ok":       "-128",
+			"float":    "1e39",
+		},
+	}
+	if _, err := GetInteger[uint8](nvs, "overflow"); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("overflow: expected ErrRange, got %v", err)
+	}
+	if _, err := GetInteger[int](nvs, "fraction"); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("fraction: expected ErrInvalidType, got %v", err)
+	}
+	if _, err := GetInteger[int](nvs, "empty"); !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("empty: expected ErrSyntax, got %v", err)
+	}
+	if _, err := GetInteger[int](nvs, "missing"); err != ErrNotFound {
+		t.Errorf("missing: expected ErrNotFound, got %v", err)
+	}
+	if v, err := GetInteger[int8](nvs, "ok"); err != nil || v != -128 {
+		t.Errorf("ok: expected -128, got %d %v", v, err)
+	}
+	if _, err := GetFloat[float32](nvs, "float"); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("float: expected ErrRange, got %v", err)
+	}
+	if v, err := GetFloat[float64](nvs, "float"); err != nil || v != 1e39 {
+		t.Errorf("float: expected 1e39, got %v %v", v, err)
+	}
+}
@@ -0,0 +1,148 @@
+package namevalue
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+// ToJSON encodes the name values as a JSON object without losing precision.
+// Decimals are written as JSON numbers instead of strings.
+// It returns an error wrapping ErrSecret if a value is a secret.
+func ToJSON(nv NameValues) ([]byte, error) {
+	return json.Marshal(toPrecise(nv.Pair))
+}
+
+// FromJSON decodes a JSON object into name values without losing precision.
+// Integers are decoded as int when they fit, other numbers are decoded as shopspring.Decimal.
+func FromJSON(data []byte) (NameValues, error) {
+	var m map[string]any
+	if err := decodePrecise(data, &m); err != nil {
+		return NameValues{}, err
+	}
+	if m == nil {
+		m = make(map[string]any)
+	}
+	nvs := NameValues{
+		Pair: m,
+	}
+	nvs.prepare()
+	return nvs, nil
+}
+
+// ToJSON encodes the name values as a JSON object without losing precision.
+func (nvp *NameValues) ToJSON() ([]byte, error) {
+	return ToJSON(*nvp)
+}
+
+// toPrecise prepares a value for storing as JSON so that decimals are written as numbers.
+// Secrets are replaced so that encoding them fails with ErrSecret.
+func toPrecise(v any) any {
+	switch t := v.(type) {
+	case Secret, *Secret:
+		return storedSecret{}
+	case ssd.Decimal:
+		return json.RawMessage(t.String())
+	case *ssd.Decimal:
+		if t == nil {
+			return nil
+		}
+		return json.RawMessage(t.String())
+	case NameValues:
+		return toPrecise(t.Pair)
+	case *NameValues:
+		if t == nil {
+			return nil
+		}
+		return toPrecise(t.Pair)
+	case map[string]any:
+		if t == nil {
+			return t
+		}
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[k] = toPrecise(e)
+		}
+		return m
+	case []any:
+		if t == nil {
+			return t
+		}
+		s := make([]any, len(t))
+		for i, e := range t {
+			s[i] = toPrecise(e)
+		}
+		return s
+	}
+	return v
+}
+
+// decodePrecise decodes JSON data into v, converting numbers with fromNumber
+func decodePrecise(data []byte, v *map[string]any) error {
+	raw, err := decodePreciseValue(data)
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		*v = nil
+		return nil
+	}
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return errors.New("namevalue: JSON value is not an object")
+	}
+	*v = m
+	return nil
+}
+
+// decodePreciseValue decodes any JSON value, converting numbers with fromNumber.
+// It returns an error if there is data after the value.
+func decodePreciseValue(data []byte) (any, error) {
+	var raw any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("namevalue: invalid data after top-level JSON value")
+	}
+	return fromPrecise(raw), nil
+}
+
+// fromPrecise converts the json.Number values in a decoded JSON value
+func fromPrecise(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		return fromNumber(t)
+	case map[string]any:
+		for k, e := range t {
+			t[k] = fromPrecise(e)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = fromPrecise(e)
+		}
+	}
+	return v
+}
+
+// fromNumber converts a JSON number to int if it is an integer that fits, otherwise to a decimal
+func fromNumber(n json.Number) any {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.Atoi(s); err == nil {
+			return i
+		}
+	}
+	d, err := ssd.NewFromString(s)
+	if err != nil {
+		return s
+	}
+	return d
+}
@@ -0,0 +1,110 @@
+package namevalue
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"Price":  ssd.RequireFromString("12345678901234567890.123456789"),
+			"big":    int64(9007199254740993),
+			"name":   "Zaldy",
+			"nested": map[string]any{"rate": ssd.RequireFromString("0.1")},
+		},
+	}
+	b, err := ToJSON(nvs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"Price":12345678901234567890.123456789,"big":9007199254740993,"name":"Zaldy","nested":{"rate":0.1}}`
+	if string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+
+	out, err := FromJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := out.Decimal("price"); !v.Equal(ssd.RequireFromString("12345678901234567890.123456789")) {
+		t.Errorf("price: lost precision, got %s", v)
+	}
+	if v := Get[int](out, "big"); v != 9007199254740993 {
+		t.Errorf("big: lost precision, got %d", v)
+	}
+	if _, err := FromJSON([]byte(`[1,2]`)); err == nil {
+		t.Errorf("expected an error for a non-object")
+	}
+	for _, in := range []string{`1 2`, `[1] [2]`, `{"a":1}x`} {
+		if _, err := decodePreciseValue([]byte(in)); err == nil {
+			t.Errorf("%s: expected an error for trailing data", in)
+		}
+	}
+	if v, err := decodePreciseValue([]byte(` [1, 2] `)); err != nil || len(v.([]any)) != 2 {
+		t.Errorf("expected surrounding space to be accepted, got %v, %v", v, err)
+	}
+}
+
+func TestNameValuesScanValue(t *testing.T) {
+	var _ driver.Valuer = NameValues{}
+	nvs := NameValues{
+		Pair: map[string]any{
+			"amount": ssd.RequireFromString("99999999999999999999.99"),
+			"count":  3,
+		},
+	}
+	v, err := nvs.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out NameValues
+	if err := out.Scan([]byte(v.(string))); err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := out.Decimal("amount"); !d.Equal(ssd.RequireFromString("99999999999999999999.99")) {
+		t.Errorf("amount: expected exact decimal, got %s", d)
+	}
+	if c, _ := out.Int("count"); c != 3 {
+		t.Errorf("count: expected 3, got %d", c)
+	}
+	if err := out.Scan(nil); err != nil || out.Pair != nil {
+		t.Errorf("expected NULL to reset the pairs")
+	}
+	if v, _ := out.Value(); v != nil {
+		t.Errorf("expected nil pairs to be stored as NULL, got %v", v)
+	}
+	if err := out.Scan(42); err == nil {
+		t.Errorf("expected an error scanning an int")
+	}
+}
+
+func TestNameValueScanValue(t *testing.T) {
+	in := NameValue[ssd.Decimal]{Name: "price", Value: ssd.RequireFromString("1.10")}
+	v, err := in.Valuer().Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != `{"name":"price","value":1.1}` {
+		t.Errorf("unexpected value %v", v)
+	}
+
+	var out NameValue[ssd.Decimal]
+	if err := out.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != "price" || !out.Value.Equal(in.Value) {
+		t.Errorf("unexpected result %v", out)
+	}
+
+	var plain NameValue[any]
+	if err := plain.Scan(`{"name":"big","value":12345678901234567890123}`); err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := plain.Value.(ssd.Decimal); !ok || d.String() != "12345678901234567890123" {
+		t.Errorf("expected exact decimal, got %T %v", plain.Value, plain.Value)
+	}
+}
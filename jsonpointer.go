@@ -0,0 +1,274 @@
+package namevalue
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPointer is returned when a JSON pointer is malformed or cannot be followed
+var ErrInvalidPointer = errors.New("invalid JSON pointer")
+
+// Pointer gets the value at a JSON pointer (RFC 6901) such as /items/0/price.
+// The second result returns the existence. The empty pointer returns all values as a map.
+//
+// Typed getters accept JSON pointers as names too, as in Decimal("/items/0/price").
+// Top-level names are folded to lower case like every other name. Segments below them
+// are matched case-insensitively unless PointerCaseSensitive is set.
+func (nvp *NameValues) Pointer(p string) (any, bool) {
+	if p == "" {
+		if !nvp.prepared {
+			nvp.prepare()
+		}
+		return nvp.Pair, true
+	}
+	if p[0] != '/' {
+		return nil, false
+	}
+	return nvp.lookup(p, "any")
+}
+
+// SetPointer sets the value at a JSON pointer (RFC 6901). Missing maps and slices on the
+// way are created: as slices when the segment is 0 or -, and as maps otherwise.
+// The segment - and the index after the last element append to a slice.
+//
+// It returns an error wrapping ErrInvalidPointer if the pointer is malformed, if an index
+// is out of range or if a value on the way is neither a map nor a slice.
+// The value is encrypted if the pointer names one of the EncryptNames.
+func (nvp *NameValues) SetPointer(p string, v any) error {
+	segs, err := parsePointer(p)
+	if err != nil {
+		return err
+	}
+	return nvp.setSegments(p, segs, v)
+}
+
+// setSegments sets the value at the segments of a name. The value is encrypted if the
+// name is one of the EncryptNames.
+func (nvp *NameValues) setSegments(name string, segs []string, v any) error {
+	if len(segs) == 0 {
+		return fmt.Errorf("%w: cannot replace all values", ErrInvalidPointer)
+	}
+	v, err := nvp.sealValue(name, v)
+	if err != nil {
+		return err
+	}
+	if !nvp.prepared {
+		nvp.prepare()
+	}
+	if nvp.Pair == nil {
+		nvp.Pair = make(map[string]any)
+	}
+	segs[0] = strings.ToLower(segs[0])
+	fold := !nvp.PointerCaseSensitive
+	_, err = walkPointer(nvp.Pair, segs, fold, true, func(c any, seg string) (any, error) {
+		return setIn(c, seg, v, fold)
+	})
+	return err
+}
+
+// resolvePointer walks a JSON pointer through nested maps and slices
+func resolvePointer(root map[string]any, p string, fold bool) (any, bool) {
+	segs, err := parsePointer(p)
+	if err != nil {
+		return nil, false
+	}
+	var (
+		cur any = root
+		ok  bool
+	)
+	for i, seg := range segs {
+		if i == 0 {
+			seg = strings.ToLower(seg)
+		}
+		if cur, ok = pointerChild(cur, seg, fold); !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// parsePointer splits a JSON pointer into its unescaped segments
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("%w: %q does not start with /", ErrInvalidPointer, p)
+	}
+	segs := strings.Split(p[1:], "/")
+	for i, s := range segs {
+		if !strings.Contains(s, "~") {
+			continue
+		}
+		for j := 0; j < len(s); j++ {
+			if s[j] == '~' && (j+1 == len(s) || (s[j+1] != '0' && s[j+1] != '1')) {
+				return nil, fmt.Errorf("%w: bad escape in %q", ErrInvalidPointer, p)
+			}
+		}
+		segs[i] = strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+	return segs, nil
+}
+
+// pointerIndex parses an array index segment, which must not have a sign or leading zeros
+func pointerIndex(seg string) (int, bool) {
+	if seg == "" || (len(seg) > 1 && seg[0] == '0') {
+		return 0, false
+	}
+	for i := 0; i < len(seg); i++ {
+		if seg[i] < '0' || seg[i] > '9' {
+			return 0, false
+		}
+	}
+	i, err := strconv.Atoi(seg)
+	return i, err == nil
+}
+
+// pointerChild returns the element of a map or slice value named by a pointer segment
+func pointerChild(v any, seg string, fold bool) (any, bool) {
+	v = deref(v)
+	if m := asMap(v); m != nil {
+		if fold {
+			return mapValue(m, seg)
+		}
+		e, ok := m[seg]
+		return e, ok
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	i, ok := pointerIndex(seg)
+	if !ok || i >= rv.Len() {
+		return nil, false
+	}
+	return rv.Index(i).Interface(), true
+}
+
+// walkPointer follows the segments below cur and calls edit with the container of the last
+// segment. It returns cur, or the container that replaces it when a slice grows or has
+// to be copied. With create, missing maps and slices on the way are created.
+func walkPointer(cur any, segs []string, fold, create bool, edit func(c any, seg string) (any, error)) (any, error) {
+	seg := segs[0]
+	cur = deref(cur)
+	if cur == nil && create {
+		if seg == "-" || seg == "0" {
+			cur = []any{}
+		} else {
+			cur = map[string]any{}
+		}
+	}
+	if len(segs) == 1 {
+		return edit(cur, seg)
+	}
+	if m := asMap(cur); m != nil {
+		key := mapKey(m, seg, fold)
+		old, ok := m[key]
+		if !ok && !create {
+			return nil, fmt.Errorf("%w: %q does not exist", ErrInvalidPointer, seg)
+		}
+		e, err := walkPointer(old, segs[1:], fold, create, edit)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = e
+		return mapContainer(cur, m), nil
+	}
+	s, ok := asSlice(cur)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot follow %q in %T", ErrInvalidPointer, seg, cur)
+	}
+	i, err := sliceIndex(s, seg, create)
+	if err != nil {
+		return nil, err
+	}
+	var old any
+	if i < len(s) {
+		old = s[i]
+	}
+	e, err := walkPointer(old, segs[1:], fold, create, edit)
+	if err != nil {
+		return nil, err
+	}
+	if i == len(s) {
+		return append(s, e), nil
+	}
+	s[i] = e
+	return s, nil
+}
+
+// setIn sets the element of a map or slice named by seg. The index after the last
+// element and - append to a slice.
+func setIn(c any, seg string, v any, fold bool) (any, error) {
+	if m := asMap(c); m != nil {
+		m[mapKey(m, seg, fold)] = v
+		return mapContainer(c, m), nil
+	}
+	s, ok := asSlice(c)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot set %q in %T", ErrInvalidPointer, seg, c)
+	}
+	i, err := sliceIndex(s, seg, true)
+	if err != nil {
+		return nil, err
+	}
+	if i == len(s) {
+		return append(s, v), nil
+	}
+	s[i] = v
+	return s, nil
+}
+
+// sliceIndex returns the index named by seg. With end, the index after the last element
+// and - are allowed and return the length of the slice.
+func sliceIndex(s []any, seg string, end bool) (int, error) {
+	if seg == "-" && end {
+		return len(s), nil
+	}
+	i, ok := pointerIndex(seg)
+	if !ok || i > len(s) || (i == len(s) && !end) {
+		return 0, fmt.Errorf("%w: index %q is out of range", ErrInvalidPointer, seg)
+	}
+	return i, nil
+}
+
+// mapKey returns the key of m that matches seg, or seg if none does
+func mapKey(m map[string]any, seg string, fold bool) string {
+	if _, ok := m[seg]; ok || !fold {
+		return seg
+	}
+	for k := range m {
+		if strings.EqualFold(k, seg) {
+			return k
+		}
+	}
+	return seg
+}
+
+// mapContainer returns the container that holds the map m taken from c with asMap.
+// Maps of other types are replaced by the copy.
+func mapContainer(c any, m map[string]any) any {
+	if nvs, ok := c.(NameValues); ok {
+		return nvs
+	}
+	return m
+}
+
+// asSlice returns a slice or array value as []any. Other slices are copied.
+func asSlice(v any) ([]any, bool) {
+	if s, ok := v.([]any); ok {
+		return s, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	s := make([]any, rv.Len())
+	for i := range s {
+		s[i] = rv.Index(i).Interface()
+	}
+	return s, true
+}
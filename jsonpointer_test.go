@@ -0,0 +1,87 @@
+package namevalue
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+func TestPointer(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{
+		"Items": []any{
+			map[string]any{"Price": "12.50", "a/b": 1, "m~n": 2},
+		},
+	}}
+
+	if v, ok := nvs.Decimal("/items/0/price"); !ok || !v.Equal(ssd.RequireFromString("12.5")) {
+		t.Errorf("expected 12.5, got %v %v", v, ok)
+	}
+	if v, _ := nvs.Int("/items/0/a~1b"); v != 1 {
+		t.Errorf("expected 1, got %d", v)
+	}
+	if v, _ := nvs.Int("/items/0/m~0n"); v != 2 {
+		t.Errorf("expected 2, got %d", v)
+	}
+	for _, p := range []string{"/items/1/price", "/items/00/price", "/items/-", "/items/0/x~2", "items/0"} {
+		if _, ok := nvs.Pointer(p); ok {
+			t.Errorf("expected %s not to resolve", p)
+		}
+	}
+	if v, ok := nvs.Pointer(""); !ok || len(v.(map[string]any)) != 1 {
+		t.Errorf("expected the empty pointer to return all values")
+	}
+
+	nvs.PointerCaseSensitive = true
+	if nvs.Exists("/items/0/price") {
+		t.Errorf("expected case-sensitive segments not to match")
+	}
+	if !nvs.Exists("/ITEMS/0/Price") {
+		t.Errorf("expected top-level names to be folded")
+	}
+}
+
+func TestSetPointer(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{
+		"tags": []string{"a"},
+		"name": "x",
+	}}
+
+	for _, s := range []struct {
+		p string
+		v any
+	}{
+		{"/db/primary/host", "localhost"},
+		{"/items/-/price", 5},
+		{"/items/1/price", 6},
+		{"/items/0/price", 7},
+		{"/tags/-", "b"},
+		{"/a~1b/c~0d", true},
+	} {
+		if err := nvs.SetPointer(s.p, s.v); err != nil {
+			t.Fatalf("%s: %v", s.p, err)
+		}
+	}
+	if v, _ := nvs.String("db.primary.host"); v != "localhost" {
+		t.Errorf("expected localhost, got %q", v)
+	}
+	if v := nvs.Ints("/items/1/price"); !reflect.DeepEqual(v, []int{6}) {
+		t.Errorf("unexpected price %v", v)
+	}
+	if v, _ := nvs.Int("/items/0/price"); v != 7 {
+		t.Errorf("expected 7, got %d", v)
+	}
+	if v := nvs.Strings("tags"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("unexpected tags %v", v)
+	}
+	if v, _ := nvs.Bool("/a~1b/c~0d"); !v {
+		t.Errorf("expected escaped segments to be set")
+	}
+
+	for _, p := range []string{"/items/5", "/name/x", "db", "/items/x~"} {
+		if err := nvs.SetPointer(p, 1); !errors.Is(err, ErrInvalidPointer) {
+			t.Errorf("%s: expected ErrInvalidPointer, got %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package namevalue
+
+import (
+	"strconv"
+	"strings"
+)
+
+type (
+	// Layer is a source of name values in a Layered configuration
+	Layer struct {
+		Name   string         // Name of the source, such as defaults, file, env or flags
+		Values NameValues     // Values of the source
+		File   string         // File the values were read from, if any
+		Lines  map[string]int // Line of each name in File, if known. Names may be paths
+	}
+	// Source is the provenance of a value in a Layered configuration
+	Source struct {
+		Layer string // Name of the layer that supplied the value
+		File  string // File of the layer, if any
+		Line  int    // Line in the file, or zero if unknown
+	}
+	// Layered stacks name values in priority order. Its embedded NameValues hold the
+	// merged values, so every getter works on the effective configuration.
+	Layered struct {
+		NameValues
+		layers []Layer
+	}
+)
+
+// NewLayered creates a layered configuration. Later layers take precedence over earlier
+// ones, so layers are usually passed as defaults, files, environment and flags.
+// Nested maps are merged key by key; other values, including slices, are replaced.
+func NewLayered(layers ...Layer) *Layered {
+	l := &Layered{
+		layers: layers,
+	}
+	l.merge()
+	return l
+}
+
+// Layers returns the layers in priority order, lowest first
+func (l *Layered) Layers() []Layer {
+	return l.layers
+}
+
+// Source returns the layer, file and line that supplied the value of a name.
+// The name may be a path or a JSON pointer. For a nested map that was merged from several
+// layers, the layer with the highest priority that has the name is returned.
+// The second result returns the existence in the merged values, so a name that a layer
+// has but a higher layer replaced, such as an element of a replaced slice, has no source.
+func (l *Layered) Source(name string) (Source, bool) {
+	if !l.NameValues.Exists(name) {
+		return Source{}, false
+	}
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		ly := &l.layers[i]
+		if !ly.Values.Exists(name) {
+			continue
+		}
+		src := Source{
+			Layer: ly.Name,
+			File:  ly.File,
+		}
+		key := pathKey(name)
+		for n, line := range ly.Lines {
+			if pathKey(n) == key {
+				src.Line = line
+				break
+			}
+		}
+		return src, true
+	}
+	return Source{}, false
+}
+
+// Explain returns the effective configuration with the source of every value, one
+// name per line sorted by name, as in db.host = localhost [file config.json:12].
+// Secrets are written as Redacted.
+func (l *Layered) Explain() string {
+	flat := l.Flatten(".")
+	keys := flat.Keys()
+	sb := strings.Builder{}
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteString(" = ")
+		if s, err := formatString(flat.Pair[k]); err == nil {
+			sb.WriteString(s)
+		} else {
+			sb.WriteString(Redacted)
+		}
+		if src, ok := l.Source(k); ok {
+			sb.WriteString(" [")
+			sb.WriteString(src.String())
+			sb.WriteString("]")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// String returns the layer name followed by the file and line if they are known
+func (s Source) String() string {
+	if s.File == "" {
+		return s.Layer
+	}
+	if s.Line == 0 {
+		return s.Layer + " " + s.File
+	}
+	return s.Layer + " " + s.File + ":" + strconv.Itoa(s.Line)
+}
+
+// merge merges the values of all layers into the embedded name values
+func (l *Layered) merge() {
+	merged := make(map[string]any)
+	for i := range l.layers {
+		nvs := &l.layers[i].Values
+		if !nvs.prepared {
+			nvs.prepare()
+		}
+		for k, v := range nvs.Pair {
+			mergeValue(merged, k, v)
+		}
+	}
+	l.Pair = merged
+	l.prepared = true
+}
+
+// mergeValue merges v into the key of m. Maps are merged recursively and copied so
+// that the layers are not changed. Nested keys are matched case-insensitively, so a
+// higher layer replaces a key that differs only in case.
+func mergeValue(m map[string]any, key string, v any) {
+	src := asMap(v)
+	if src == nil {
+		m[key] = cloneValue(v)
+		return
+	}
+	dst, ok := m[key].(map[string]any)
+	if !ok {
+		dst = make(map[string]any, len(src))
+		m[key] = dst
+	}
+	for k, e := range src {
+		mergeValue(dst, mapKey(dst, k, true), e)
+	}
+}
+
+// pathKey normalizes a name, path or JSON pointer for comparison
+func pathKey(name string) string {
+	var (
+		segs []string
+		err  error
+		ok   bool
+	)
+	if strings.HasPrefix(name, "/") {
+		segs, err = parsePointer(name)
+		ok = err == nil
+	} else {
+		segs, ok = parsePath(name, ".")
+	}
+	if !ok {
+		return strings.ToLower(name)
+	}
+	lower := make([]string, len(segs))
+	for i, s := range segs {
+		lower[i] = strings.ToLower(s)
+	}
+	return strings.Join(lower, "\x00")
+}
@@ -0,0 +1,82 @@
+package namevalue
+
+import (
+	"testing"
+)
+
+func TestLayered(t *testing.T) {
+	defaults := NameValues{Pair: map[string]any{
+		"port": 8080,
+		"db":   map[string]any{"host": "localhost", "port": 5432},
+		"tags": []any{"a", "b"},
+	}}
+	file := NameValues{Pair: map[string]any{
+		"DB":   map[string]any{"host": "db.internal"},
+		"tags": []any{"c"},
+	}}
+	flags := NameValues{Pair: map[string]any{"port": "9090"}}
+
+	l := NewLayered(
+		Layer{Name: "defaults", Values: defaults},
+		Layer{Name: "file", Values: file, File: "config.json", Lines: map[string]int{"db.host": 3}},
+		Layer{Name: "flags", Values: flags},
+	)
+
+	if v, _ := l.Int("port"); v != 9090 {
+		t.Errorf("expected 9090, got %d", v)
+	}
+	if v, _ := l.String("db.host"); v != "db.internal" {
+		t.Errorf("expected db.internal, got %q", v)
+	}
+	if v, _ := l.Int("/db/port"); v != 5432 {
+		t.Errorf("expected the nested default to be kept, got %d", v)
+	}
+	if v := l.Strings("tags"); len(v) != 1 || v[0] != "c" {
+		t.Errorf("expected slices to be replaced, got %v", v)
+	}
+	if v, _ := defaults.String("db.host"); v != "localhost" {
+		t.Errorf("expected the layers not to be changed")
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"port", "flags"},
+		{"/DB/host", "file config.json:3"},
+		{"db.port", "defaults"},
+		{"tags", "file config.json"},
+	}
+	for _, tt := range tests {
+		src, ok := l.Source(tt.name)
+		if !ok || src.String() != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, src)
+		}
+	}
+	for _, name := range []string{"missing", "tags.1", "/tags/1"} {
+		if src, ok := l.Source(name); ok {
+			t.Errorf("%s: expected no source for a name missing from the merged values, got %s", name, src)
+		}
+	}
+
+	expected := "db.host = db.internal [file config.json:3]\n" +
+		"db.port = 5432 [defaults]\n" +
+		"port = 9090 [flags]\n" +
+		"tags.0 = c [file config.json]\n"
+	if v := l.Explain(); v != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, v)
+	}
+}
+
+func TestLayeredFoldsNestedKeys(t *testing.T) {
+	l := NewLayered(
+		Layer{Name: "one", Values: NameValues{Pair: map[string]any{"db": map[string]any{"Host": "a"}}}},
+		Layer{Name: "two", Values: NameValues{Pair: map[string]any{"db": map[string]any{"host": "b"}}}},
+	)
+	if v, _ := l.String("db.host"); v != "b" {
+		t.Errorf("expected the higher layer to replace the key, got %q", v)
+	}
+	if expected, v := "db.host = b [two]\n", l.Explain(); v != expected {
+		t.Errorf("expected %q, got %q", expected, v)
+	}
+}
@@ -0,0 +1,202 @@
+package namevalue
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type (
+	// ElementError is the conversion error of a single list element
+	ElementError struct {
+		Index int
+		Err   error
+	}
+	// ListErrors is the list of element errors returned by GetList
+	ListErrors []*ElementError
+)
+
+// Error returns the element index and its error
+func (ee *ElementError) Error() string {
+	return "element " + strconv.Itoa(ee.Index) + ": " + ee.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (ee *ElementError) Unwrap() error {
+	return ee.Err
+}
+
+// Error returns all element errors separated by semicolons
+func (le ListErrors) Error() string {
+	msgs := make([]string, len(le))
+	for i, ee := range le {
+		msgs[i] = ee.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the element errors so that errors.Is and errors.As can inspect them
+func (le ListErrors) Unwrap() []error {
+	errs := make([]error, len(le))
+	for i, ee := range le {
+		errs[i] = ee
+	}
+	return errs
+}
+
+// GetList gets the value from the collection of NameValues by name as a list of T.
+//
+// The value may be a stored slice or array, a JSON array in a string, or a delimited string
+// that follows CSV quoting rules. The delimiter is the ListSeparator of the name values.
+// Every element is converted like the scalar getters convert a value.
+//
+// Elements that cannot be converted are left as the zero value of T and reported in ListErrors.
+// It returns ErrNotFound if the name does not exist.
+func GetList[T any](nvs NameValues, name string) ([]T, error) {
+	tmp, exists := nvs.lookup(name, "[]"+typeName[T]())
+	if !exists {
+		return []T{}, ErrNotFound
+	}
+	return toList(&nvs, tmp, func(v any) (T, error) {
+		return convertTo[T](&nvs, v)
+	})
+}
+
+// listOf gets the name value as a list, leaving elements that cannot be converted as zero values
+func listOf[T any](nvp *NameValues, name string, conv func(any) (T, error)) []T {
+	tmp, exists := nvp.lookup(name, "[]"+typeName[T]())
+	if !exists {
+		return []T{}
+	}
+	val, _ := toList(nvp, tmp, conv)
+	return val
+}
+
+// toList splits a value into elements and converts each of them
+func toList[T any](nvp *NameValues, v any, conv func(any) (T, error)) ([]T, error) {
+	elems, err := nvp.elements(v)
+	if err != nil {
+		return []T{}, err
+	}
+	var errs ListErrors
+	res := make([]T, len(elems))
+	for i, e := range elems {
+		if res[i], err = conv(reveal(e)); err != nil {
+			errs = append(errs, &ElementError{Index: i, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return res, errs
+	}
+	return res, nil
+}
+
+// elements splits a value into list elements
+func (nvp *NameValues) elements(v any) ([]any, error) {
+	switch t := v.(type) {
+	case nil:
+		return []any{}, nil
+	case []any:
+		return t, nil
+	case []byte:
+		return []any{t}, nil
+	case string:
+		return nvp.splitList(t)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{v}, nil
+	}
+	res := make([]any, rv.Len())
+	for i := range res {
+		res[i] = rv.Index(i).Interface()
+	}
+	return res, nil
+}
+
+// splitList splits a string that contains a JSON array or delimited elements
+func (nvp *NameValues) splitList(s string) ([]any, error) {
+	trim := strings.TrimSpace(s)
+	if trim == "" {
+		return []any{}, nil
+	}
+	if strings.HasPrefix(trim, "[") && strings.HasSuffix(trim, "]") {
+		if arr, err := decodePreciseValue([]byte(trim)); err == nil {
+			if elems, ok := arr.([]any); ok {
+				return elems, nil
+			}
+		}
+	}
+	elems, err := splitDelimited(s, nvp.listSeparator(), !nvp.ListKeepSpace)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]any, len(elems))
+	for i, e := range elems {
+		res[i] = e
+	}
+	return res, nil
+}
+
+// splitDelimited splits a string by sep. Elements may be enclosed in double quotes to
+// contain the separator, and a doubled double quote inside quotes is a literal quote.
+// White space around elements and outside quotes is removed if trim is set.
+func splitDelimited(s string, sep rune, trim bool) ([]string, error) {
+	var (
+		res    []string
+		sb     strings.Builder
+		quoted bool // inside quotes
+		wasQ   bool // the current element was quoted
+	)
+	flush := func() {
+		e := sb.String()
+		if trim && !wasQ {
+			e = strings.TrimSpace(e)
+		}
+		res = append(res, e)
+		sb.Reset()
+		wasQ = false
+	}
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case quoted && r == '"':
+			if i+1 < len(rs) && rs[i+1] == '"' {
+				sb.WriteRune('"')
+				i++
+				continue
+			}
+			quoted = false
+		case quoted:
+			sb.WriteRune(r)
+		case r == sep:
+			flush()
+		case r == '"' && strings.TrimSpace(sb.String()) == "" && !wasQ:
+			// A quote opens an element only at its start
+			if trim {
+				sb.Reset()
+			}
+			quoted, wasQ = true, true
+		case wasQ && trim && unicode.IsSpace(r):
+			// Skip white space after the closing quote
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, errors.New("namevalue: unterminated quote in list")
+	}
+	flush()
+	return res, nil
+}
+
+// listSeparator returns the list separator, which defaults to a comma
+func (nvp *NameValues) listSeparator() rune {
+	if nvp.ListSeparator == 0 {
+		return ','
+	}
+	return nvp.ListSeparator
+}
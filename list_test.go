@@ -0,0 +1,89 @@
+package namevalue
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+func TestNVLists(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"csv":     ` apple, "banana, ripe" ,cherry `,
+			"json":    `[1, 2, 3]`,
+			"any":     []any{"4", 5, 6.0},
+			"ints":    []int{7, 8},
+			"strs":    []string{"true", "off", "YES"},
+			"decs":    `["1.10", 2.25]`,
+			"single":  "42",
+			"typed":   42,
+			"empty":   "",
+			"invalid": "1,x,3",
+		},
+	}
+
+	if v := nvs.Strings("csv"); !reflect.DeepEqual(v, []string{"apple", "banana, ripe", "cherry"}) {
+		t.Errorf("csv: unexpected %q", v)
+	}
+	if v := nvs.Ints("json"); !reflect.DeepEqual(v, []int{1, 2, 3}) {
+		t.Errorf("json: unexpected %v", v)
+	}
+	if v := nvs.Int64s("any"); !reflect.DeepEqual(v, []int64{4, 5, 6}) {
+		t.Errorf("any: unexpected %v", v)
+	}
+	if v := nvs.Float64s("ints"); !reflect.DeepEqual(v, []float64{7, 8}) {
+		t.Errorf("ints: unexpected %v", v)
+	}
+	if v := nvs.Bools("strs"); !reflect.DeepEqual(v, []bool{true, false, true}) {
+		t.Errorf("strs: unexpected %v", v)
+	}
+	if v := nvs.Decimals("decs"); len(v) != 2 || !v[0].Equal(ssd.RequireFromString("1.1")) || !v[1].Equal(ssd.RequireFromString("2.25")) {
+		t.Errorf("decs: unexpected %v", v)
+	}
+	if v := nvs.Uint8s("single"); !reflect.DeepEqual(v, []uint8{42}) {
+		t.Errorf("single: unexpected %v", v)
+	}
+	if v := nvs.Strings("typed"); !reflect.DeepEqual(v, []string{"42"}) {
+		t.Errorf("typed: unexpected %v", v)
+	}
+	if v := nvs.Strings("empty"); len(v) != 0 {
+		t.Errorf("empty: unexpected %v", v)
+	}
+	if v := nvs.Ints("missing"); v == nil || len(v) != 0 {
+		t.Errorf("missing: expected an empty array, got %v", v)
+	}
+	if v := nvs.Ints("invalid"); !reflect.DeepEqual(v, []int{1, 0, 3}) {
+		t.Errorf("invalid: unexpected %v", v)
+	}
+}
+
+func TestGetList(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"ports": "80; 443; x; 70000",
+		},
+		ListSeparator: ';',
+	}
+	v, err := GetList[uint16](nvs, "ports")
+	if !reflect.DeepEqual(v, []uint16{80, 443, 0, 0}) {
+		t.Errorf("unexpected %v", v)
+	}
+	var le ListErrors
+	if !errors.As(err, &le) || len(le) != 2 || le[0].Index != 2 || le[1].Index != 3 {
+		t.Fatalf("expected errors for elements 2 and 3, got %v", err)
+	}
+	if !errors.Is(le[1], strconv.ErrRange) {
+		t.Errorf("expected a range error, got %v", le[1])
+	}
+	if _, err := GetList[string](nvs, "missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	nvs.ListKeepSpace = true
+	if v, _ := GetList[string](nvs, "ports"); !reflect.DeepEqual(v, []string{"80", " 443", " x", " 70000"}) {
+		t.Errorf("unexpected %q", v)
+	}
+}
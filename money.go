@@ -0,0 +1,168 @@
+package namevalue
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+// RoundingMode is the rounding applied when a decimal is scaled
+type RoundingMode int
+
+// Rounding modes
+const (
+	RoundHalfUp   RoundingMode = iota // Half away from zero, 2.5 becomes 3 and -2.5 becomes -3
+	RoundHalfEven                     // Half to even, also known as banker's rounding
+	RoundUp                           // Away from zero
+	RoundDown                         // Toward zero, which truncates
+	RoundCeiling                      // Toward positive infinity
+	RoundFloor                        // Toward negative infinity
+)
+
+// ErrScale is returned when a decimal has too few or too many decimal places
+var ErrScale = errors.New("decimal places are out of range")
+
+// Money is an amount in a currency
+type Money struct {
+	Amount   ssd.Decimal
+	Currency string // ISO 4217 code, or empty if the value has no currency
+}
+
+// CurrencyMinorUnits is the number of decimal places of the minor unit of ISO 4217 currencies
+var CurrencyMinorUnits = map[string]int32{
+	"AED": 2, "ARS": 2, "AUD": 2, "BDT": 2, "BGN": 2, "BHD": 3, "BRL": 2, "CAD": 2,
+	"CHF": 2, "CLP": 0, "CNY": 2, "COP": 2, "CZK": 2, "DKK": 2, "EGP": 2, "EUR": 2,
+	"GBP": 2, "HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KES": 2, "KRW": 0, "KWD": 3, "LKR": 2, "LYD": 3, "MAD": 2,
+	"MXN": 2, "MYR": 2, "NGN": 2, "NOK": 2, "NZD": 2, "OMR": 3, "PEN": 2, "PHP": 2,
+	"PKR": 2, "PLN": 2, "QAR": 2, "RON": 2, "RSD": 2, "RUB": 2, "SAR": 2, "SEK": 2,
+	"SGD": 2, "THB": 2, "TND": 3, "TRY": 2, "TWD": 2, "UAH": 2, "UGX": 0, "USD": 2,
+	"VND": 0, "XAF": 0, "XOF": 0, "ZAR": 2,
+}
+
+// DecimalScaled returns the name value as shopspring.Decimal rounded to scale decimal places
+// with the rounding mode. The second result returns the existence.
+func (nvp *NameValues) DecimalScaled(name string, scale int32, mode RoundingMode) (ssd.Decimal, bool) {
+	value, exists := nvp.Decimal(name)
+	if !exists {
+		return value, exists
+	}
+	return roundDecimal(value, scale, mode), exists
+}
+
+// DecimalScale returns the name value as shopspring.Decimal and checks that it is written
+// with at least minScale and at most maxScale decimal places. Trailing zeros are counted,
+// so 12.50 has two decimal places.
+//
+// It returns ErrNotFound if the name does not exist and ErrScale if the check fails.
+func (nvp *NameValues) DecimalScale(name string, minScale, maxScale int32) (ssd.Decimal, error) {
+	tmp, exists := nvp.lookup(name, "decimal")
+	if !exists {
+		return ssd.Decimal{}, ErrNotFound
+	}
+	value, err := toDecimalIn(nvp, tmp)
+	if err != nil {
+		return value, err
+	}
+	scale := max(-value.Exponent(), 0)
+	if scale < minScale || scale > maxScale {
+		return value, fmt.Errorf("%w: %s has %d, expected %d to %d", ErrScale, value, scale, minScale, maxScale)
+	}
+	return value, nil
+}
+
+// Money returns the name value as Money. The second result returns the existence.
+//
+// Strings may have an ISO 4217 code before or after the amount, such as USD 12.50
+// or 12.50 EUR. The amount is converted like Decimal converts a value.
+func (nvp *NameValues) Money(name string) (Money, bool) {
+	tmp, exists := nvp.lookup(name, "money")
+	if !exists {
+		return Money{}, exists
+	}
+	val, _ := nvp.toMoney(tmp)
+	return val, exists
+}
+
+// PtrMoney returns the name value as pointer to Money. The second result returns the existence.
+func (nvp *NameValues) PtrMoney(name string) (*Money, bool) {
+	return ptrOf(nvp.Money(name))
+}
+
+// MinorUnits returns the number of decimal places of the minor unit of a currency
+func MinorUnits(currency string) (int32, bool) {
+	mu, ok := CurrencyMinorUnits[strings.ToUpper(currency)]
+	return mu, ok
+}
+
+// Round rounds the amount to the minor unit of its currency with the rounding mode.
+// Amounts without a known currency are returned as is.
+func (m Money) Round(mode RoundingMode) Money {
+	mu, ok := MinorUnits(m.Currency)
+	if !ok {
+		return m
+	}
+	return Money{
+		Amount:   roundDecimal(m.Amount, mu, mode),
+		Currency: m.Currency,
+	}
+}
+
+// String returns the currency code followed by the amount in the minor unit of the currency
+func (m Money) String() string {
+	amt := m.Amount.String()
+	if mu, ok := MinorUnits(m.Currency); ok && -m.Amount.Exponent() <= mu {
+		amt = m.Amount.StringFixed(mu)
+	}
+	if m.Currency == "" {
+		return amt
+	}
+	return m.Currency + " " + amt
+}
+
+// toMoney converts a value to Money
+func (nvp *NameValues) toMoney(v any) (Money, error) {
+	switch t := v.(type) {
+	case Money:
+		return t, nil
+	case string:
+		s := strings.TrimSpace(t)
+		var code string
+		if f := strings.Fields(s); len(f) > 1 {
+			if _, ok := MinorUnits(f[0]); ok {
+				code, s = f[0], strings.TrimSpace(s[len(f[0]):])
+			} else if _, ok := MinorUnits(f[len(f)-1]); ok {
+				code, s = f[len(f)-1], strings.TrimSpace(s[:len(s)-len(f[len(f)-1])])
+			}
+		}
+		amt, err := toDecimalIn(nvp, s)
+		if err != nil {
+			return Money{}, err
+		}
+		return Money{Amount: amt, Currency: strings.ToUpper(code)}, nil
+	}
+	amt, err := toDecimalIn(nvp, v)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amt}, nil
+}
+
+// roundDecimal rounds a decimal to scale decimal places with the rounding mode
+func roundDecimal(d ssd.Decimal, scale int32, mode RoundingMode) ssd.Decimal {
+	switch mode {
+	case RoundHalfEven:
+		return d.RoundBank(scale)
+	case RoundUp:
+		return d.RoundUp(scale)
+	case RoundDown:
+		return d.RoundDown(scale)
+	case RoundCeiling:
+		return d.RoundCeil(scale)
+	case RoundFloor:
+		return d.RoundFloor(scale)
+	}
+	return d.Round(scale)
+}
@@ -0,0 +1,97 @@
+package namevalue
+
+import (
+	"errors"
+	"testing"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+func TestDecimalScaled(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"half":     "2.345",
+			"negative": -2.345,
+			"typed":    7,
+		},
+	}
+	tests := []struct {
+		name     string
+		mode     RoundingMode
+		expected string
+	}{
+		{"half", RoundHalfUp, "2.35"},
+		{"half", RoundHalfEven, "2.34"},
+		{"half", RoundDown, "2.34"},
+		{"half", RoundUp, "2.35"},
+		{"negative", RoundCeiling, "-2.34"},
+		{"negative", RoundFloor, "-2.35"},
+		{"negative", RoundHalfUp, "-2.35"},
+		{"typed", RoundHalfUp, "7"},
+	}
+	for _, tt := range tests {
+		v, exists := nvs.DecimalScaled(tt.name, 2, tt.mode)
+		if !exists || !v.Equal(ssd.RequireFromString(tt.expected)) {
+			t.Errorf("%s/%d: expected %s, got %s", tt.name, tt.mode, tt.expected, v)
+		}
+	}
+}
+
+func TestDecimalScale(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"price": "12.50",
+			"rate":  "0.12345",
+			"whole": 12,
+		},
+	}
+	if v, err := nvs.DecimalScale("price", 2, 2); err != nil || !v.Equal(ssd.RequireFromString("12.5")) {
+		t.Errorf("price: unexpected %s %v", v, err)
+	}
+	if _, err := nvs.DecimalScale("rate", 0, 4); !errors.Is(err, ErrScale) {
+		t.Errorf("rate: expected ErrScale, got %v", err)
+	}
+	if _, err := nvs.DecimalScale("whole", 1, 2); !errors.Is(err, ErrScale) {
+		t.Errorf("whole: expected ErrScale, got %v", err)
+	}
+	if _, err := nvs.DecimalScale("missing", 0, 2); err != ErrNotFound {
+		t.Errorf("missing: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestNVMoney(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"prefix":  "USD 1,012.50",
+			"suffix":  "12.5 eur",
+			"yen":     "JPY 1234.5",
+			"plain":   "12.50",
+			"typed":   12.5,
+			"invalid": "USD twelve",
+		},
+	}
+	tests := []struct {
+		name, amount, currency, str string
+	}{
+		{"prefix", "1012.50", "USD", "USD 1012.50"},
+		{"suffix", "12.5", "EUR", "EUR 12.50"},
+		{"yen", "1234.5", "JPY", "JPY 1234.5"},
+		{"plain", "12.5", "", "12.5"},
+		{"typed", "12.5", "", "12.5"},
+	}
+	for _, tt := range tests {
+		m, exists := nvs.Money(tt.name)
+		if !exists || !m.Amount.Equal(ssd.RequireFromString(tt.amount)) || m.Currency != tt.currency {
+			t.Errorf("%s: unexpected %v", tt.name, m)
+		}
+		if m.String() != tt.str {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.str, m.String())
+		}
+	}
+	if m, _ := nvs.Money("yen"); m.Round(RoundHalfEven).String() != "JPY 1234" {
+		t.Errorf("yen: unexpected rounding %s", m.Round(RoundHalfEven))
+	}
+	if m, exists := nvs.Money("invalid"); !exists || !m.Amount.IsZero() || m.Currency != "" {
+		t.Errorf("invalid: expected zero money, got %v", m)
+	}
+}
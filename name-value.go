@@ -7,6 +7,7 @@ package namevalue
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -23,8 +24,33 @@ type (
 	}
 	// NameValues is a struct to manage value structs
 	NameValues struct {
-		Pair     map[string]any
-		prepared bool
+		Pair map[string]any
+		// OnMiss is called when a getter looks up a name that does not exist.
+		// The nearest existing name is passed, or an empty string if no name is close enough.
+		OnMiss func(name, nearest string)
+		// Location is used for times without a time zone. Defaults to UTC
+		Location *time.Location
+		// ListSeparator delimits the elements of a list in a string. Defaults to a comma
+		ListSeparator rune
+		// ListKeepSpace keeps the white space around list elements instead of trimming it
+		ListKeepSpace bool
+		// NumberFormat is used to parse numeric strings. Defaults to NumberFormatEN, not strict
+		NumberFormat *NumberFormat
+		// Booleans is the vocabulary of true and false strings. Defaults to DefaultBoolVocabulary
+		Booleans *BoolVocabulary
+		// AllowSecrets lets Interpolate inline the values of secrets instead of Redacted
+		AllowSecrets bool
+		// Keyring decrypts encrypted values for the getters and encrypts the values of EncryptNames when they are set
+		Keyring *Keyring
+		// OnDecryptError is called when a getter reads an encrypted value that cannot be decrypted.
+		// The getter then returns the value as nil.
+		OnDecryptError func(name string, err error)
+		// EncryptNames are the names, paths or JSON pointers whose values Set and SetPointer encrypt
+		EncryptNames []string
+		// PointerCaseSensitive matches the segments of JSON pointers below the top-level names exactly
+		PointerCaseSensitive bool
+		tracker              *tracker
+		prepared             bool
 	}
 )
 
@@ -45,16 +71,77 @@ func (nvp *NameValues) prepare() {
 	nvp.prepared = true
 }
 
-// Exists checks if the key or name exists. It returns the index of the element if found, -1 if not found.
-func (nvp *NameValues) Exists(name string) bool {
+// find gets the raw value of a name, which may be a path or a JSON pointer into nested values
+func (nvp *NameValues) find(name string) (any, bool) {
 	if !nvp.prepared {
 		nvp.prepare()
 	}
+	pointer := name
+	name = strings.ToLower(name)
+	if v, exists := nvp.Pair[name]; exists {
+		return v, exists
+	}
+	if strings.HasPrefix(pointer, "/") {
+		return resolvePointer(nvp.Pair, pointer, !nvp.PointerCaseSensitive)
+	}
+	if strings.ContainsAny(name, ".[") {
+		return resolvePath(nvp.Pair, name)
+	}
+	return nil, false
+}
+
+// lookup gets the value of a name requested as typ, recording the access when tracking
+// and reporting misses to OnMiss. Values requested as any are returned as stored, so Plain
+// and PtrPlain return pointers and secrets. For the typed getters, pointers are dereferenced,
+// nil pointers are returned as nil and secrets are unwrapped.
+// Encrypted values are decrypted as secrets if there is a Keyring, and returned as nil
+// after reporting to OnDecryptError if they cannot be decrypted.
+func (nvp *NameValues) lookup(name, typ string) (any, bool) {
+	v, exists := nvp.find(name)
 	name = strings.ToLower(name)
-	_, exists := nvp.Pair[name]
+	if nvp.tracker != nil {
+		nvp.tracker.record(name, typ)
+	}
+	if !exists && nvp.OnMiss != nil {
+		nvp.OnMiss(name, Nearest(name, nvp.Keys()))
+	}
+	if s, ok := deref(v).(string); ok && nvp.Keyring != nil && IsEncrypted(s) {
+		var err error
+		if v, err = nvp.Keyring.decryptValue(s); err != nil && nvp.OnDecryptError != nil {
+			nvp.OnDecryptError(name, err)
+		}
+	}
+	if typ == "any" {
+		return v, exists
+	}
+	return reveal(v), exists
+}
+
+// Exists checks if the key or name exists. The name may be a path or a JSON pointer into nested values.
+func (nvp *NameValues) Exists(name string) bool {
+	_, exists := nvp.find(name)
 	return exists
 }
 
+// IsNull checks if the name exists and its value is nil or a nil pointer.
+func (nvp *NameValues) IsNull(name string) bool {
+	v, exists := nvp.find(name)
+	return exists && deref(v) == nil
+}
+
+// Keys returns the names in the collection, folded to lower case and sorted.
+func (nvp *NameValues) Keys() []string {
+	if !nvp.prepared {
+		nvp.prepare()
+	}
+	keys := make([]string, 0, len(nvp.Pair))
+	for n := range nvp.Pair {
+		keys = append(keys, n)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // // Get gets the value from the collection of NameValues by name
 // //
 // // This function requires version 1.18+
@@ -109,50 +196,20 @@ func (nvp *NameValues) Exists(name string) bool {
 
 // GetPtr gets the value from the collection of NameValues by name and returns a pointer
 //
-// // This function returns nil if it does not find the name.
+// This function returns nil if it does not find the name, if the value is nil or a nil pointer,
+// or if the value cannot be converted to T.
 //
 // This function requires version 1.18+
-func GetPtr[T constraints.Ordered | bool](nvs NameValues, name string) *T {
-	if !nvs.prepared {
-		nvs.prepare()
-	}
-
-	tmp, here := nvs.Pair[strings.ToLower(name)]
+func GetPtr[T constraints.Ordered | bool | time.Time](nvs NameValues, name string) *T {
+	tmp, here := nvs.lookup(name, typeName[T]())
 	if !here || tmp == nil {
 		return nil
 	}
-
-	tpt := any(*new(T))
-	value := new(T)
-
-	// If the value is a string and the inferred type is otherwise
-	// try to convert, else just convery via inferred type
-	switch t := tmp.(type) {
-	case string:
-		switch tpt.(type) {
-		case int:
-			val, _ := strconv.ParseInt(t, 10, 32)
-			*value = any(int(val)).(T)
-		case int64:
-			val, _ := strconv.ParseInt(t, 10, 64)
-			*value = any(val).(T)
-		case bool:
-			val, _ := strconv.ParseBool(t)
-			*value = any(val).(T)
-		case float32:
-			val, _ := strconv.ParseFloat(t, 32)
-			*value = any(val).(T)
-		case float64:
-			val, _ := strconv.ParseFloat(t, 64)
-			*value = any(val).(T)
-		case string:
-			*value = any(t).(T)
-		}
-	default:
-		*value = t.(T)
+	value, err := convertTo[T](&nvs, tmp)
+	if err != nil {
+		return nil
 	}
-
-	return value
+	return &value
 }
 
 // Get gets the value from the collection of NameValues by name.
@@ -160,7 +217,7 @@ func GetPtr[T constraints.Ordered | bool](nvs NameValues, name string) *T {
 // This function returns the zero value of T if it does not find the name.
 //
 // This function requires version 1.18+
-func Get[T constraints.Ordered | bool](nvs NameValues, name string) T {
+func Get[T constraints.Ordered | bool | time.Time](nvs NameValues, name string) T {
 	value := GetPtr[T](nvs, name)
 	if value == nil {
 		return getZero[T]()
@@ -178,16 +235,12 @@ func Get[T constraints.Ordered | bool](nvs NameValues, name string) T {
 
 // String returns the name value as string. The second result returns the existence.
 func (nvp *NameValues) String(name string) (string, bool) {
-	if !nvp.prepared {
-		nvp.prepare()
-	}
 	var (
 		tmp          any
 		exists, conv bool
 		str, val     string
 	)
-	name = strings.ToLower(name)
-	tmp, exists = nvp.Pair[name]
+	tmp, exists = nvp.lookup(name, "string")
 	if !exists {
 		return str, exists
 	}
@@ -200,253 +253,101 @@ func (nvp *NameValues) String(name string) (string, bool) {
 
 // Strings returns the values as a string array.
 // If the name does not exist, this function will return an empty string array
-// If the value is comma-separated, all elements delimited by the comma will be returned as an array
+// The value may be a stored array, a JSON array or a string delimited by the ListSeparator.
+// Elements may be quoted to contain the separator.
 func (nvp *NameValues) Strings(name string) []string {
-	value, exists := nvp.String(name)
-	if !exists {
-		return []string{}
-	}
-	if strings.Contains(value, ",") {
-		return strings.Split(value, ",")
-	}
-	var result [1]string
-	result[0] = value
-	return result[:]
+	return listOf(nvp, name, toString)
 }
 
 // Int returns the name value as int. The second result returns the existence.
+//
+// Numeric strings may have 0x, 0o or 0b prefixes and _ separators, or
+// be formatted with the NumberFormat of the name values.
+// Values that are out of range return zero.
 func (nvp *NameValues) Int(name string) (int, bool) {
-	if !nvp.prepared {
-		nvp.prepare()
-	}
-	var (
-		conv, exists bool
-		tmp          any
-		val          int
-		str          string
-		err          error
-	)
-	name = strings.ToLower(name)
-	// Check if the key exists in the map
-	tmp, exists = nvp.Pair[name]
-	if !exists {
-		return val, exists
-	}
-	// Attempt to convert the interface to int
-	val, conv = tmp.(int)
-	if conv {
-		return val, exists
-	}
-	// If it did not succeed, try converting to string first
-	// and manually convert to int
-	str, conv = tmp.(string)
-	if !conv {
-		return val, exists
-	}
-	val, err = strconv.Atoi(str)
-	if err != nil {
-		return val, exists
-	}
-	return val, exists
+	return getInteger[int](nvp, name)
 }
 
 // Ints returns the values as an int array
 // If the name does not exist, this function will return an empty int array
+// Elements that cannot be converted are zero. Use GetList to get the element errors.
 func (nvp *NameValues) Ints(name string) []int {
-	value, exists := nvp.Int(name)
-	if !exists {
-		return []int{}
-	}
-	var result [1]int
-	result[0] = value
-	return result[:]
+	return listOf(nvp, name, integerIn[int](nvp))
 }
 
 // Int64 returns the name value as int64. The second result returns the existence.
+//
+// Numeric strings may have 0x, 0o or 0b prefixes and _ separators, or
+// be formatted with the NumberFormat of the name values.
+// Values that are out of range return zero.
 func (nvp *NameValues) Int64(name string) (int64, bool) {
-	if !nvp.prepared {
-		nvp.prepare()
-	}
-	var (
-		conv, exists bool
-		tmp          any
-		val          int64
-		str          string
-		err          error
-	)
-	name = strings.ToLower(name)
-	// Check if the key exists in the map
-	tmp, exists = nvp.Pair[name]
-	if !exists {
-		return val, exists
-	}
-	// Attempt to convert the interface to int64
-	val, conv = tmp.(int64)
-	if conv {
-		return val, exists
-	}
-	// If it did not succeed, try converting to string first
-	// and manually convert to int64
-	str, conv = tmp.(string)
-	if !conv {
-		return val, exists
-	}
-	val, err = strconv.ParseInt(str, 10, 64)
-	if err != nil {
-		return val, exists
-	}
-	return val, exists
+	return getInteger[int64](nvp, name)
 }
 
 // Int64s returns the values as an int64 array
 // If the name does not exist, this function will return an empty int64 array
+// Elements that cannot be converted are zero. Use GetList to get the element errors.
 func (nvp *NameValues) Int64s(name string) []int64 {
-	value, exists := nvp.Int64(name)
-	if !exists {
-		return []int64{}
-	}
-	var result [1]int64
-	result[0] = value
-	return result[:]
+	return listOf(nvp, name, integerIn[int64](nvp))
 }
 
 // Plain returns the name value as interface{}. The second result returns the existence.
 func (nvp *NameValues) Plain(name string) (interface{}, bool) {
-	if !nvp.prepared {
-		nvp.prepare()
-	}
-	name = strings.ToLower(name)
-	tmp, exists := nvp.Pair[name]
+	tmp, exists := nvp.lookup(name, "any")
 	return tmp, exists
 }
 
-// Bool returns the name value as boolean. It automatically convers 'true', 'yes', '1', '-1' and 'on' to boolean.
-// The second result returns the existence.
+// Bool returns the name value as boolean. The second result returns the existence.
+//
+// Strings are compared case-insensitively with the Booleans vocabulary of the name values,
+// which defaults to DefaultBoolVocabulary. Numbers are true if they are 1 or -1.
+// Values that are neither true nor false return false. Use BoolState to tell them apart.
 func (nvp *NameValues) Bool(name string) (bool, bool) {
-	value, exists := nvp.String(name)
-	if !exists {
-		return exists, exists
-	}
-	return (value == "true" || value == "yes" || value == "1" || value == "-1" || value == "on"), true
+	value, _, exists := nvp.BoolState(name)
+	return value, exists
 }
 
 // Bools returns the values as a boolean array
 // If the name does not exist, this function will return an empty boolean array
+// Elements that cannot be converted are false. Use GetList to get the element errors.
 func (nvp *NameValues) Bools(name string) []bool {
-	value, exists := nvp.Bool(name)
-	if !exists {
-		return []bool{}
-	}
-	var result [1]bool
-	result[0] = value
-	return result[:]
+	return listOf(nvp, name, nvp.Booleans.toBool)
 }
 
 // Float64 returns the name value as float64. The second result returns the existence.
+// Strings are parsed with the NumberFormat of the name values.
 func (nvp *NameValues) Float64(name string) (float64, bool) {
-	if !nvp.prepared {
-		nvp.prepare()
-	}
-	var (
-		conv, exists bool
-		tmp          any
-		val          float64
-		str          string
-		err          error
-	)
-	name = strings.ToLower(name)
-	// Check if the key exists in the map
-	tmp, exists = nvp.Pair[name]
+	tmp, exists := nvp.lookup(name, "float64")
 	if !exists {
-		return val, exists
-	}
-	// Attempt to convert the interface to float64
-	val, conv = tmp.(float64)
-	if conv {
-		return val, exists
-	}
-	// If it did not succeed, try converting to string first
-	// and manually convert to int64
-	str, conv = tmp.(string)
-	if !conv {
-		return val, exists
-	}
-	val, err = strconv.ParseFloat(str, 64)
-	if err != nil {
-		return val, exists
+		return 0, exists
 	}
+	val, _ := toFloatIn[float64](nvp, tmp)
 	return val, exists
 }
 
 // Float64s returns the values as a float64 array
 // If the name does not exist, this function will return an empty float64 array
+// Elements that cannot be converted are zero. Use GetList to get the element errors.
 func (nvp *NameValues) Float64s(name string) []float64 {
-	value, exists := nvp.Float64(name)
-	if !exists {
-		return []float64{}
-	}
-	var result [1]float64
-	result[0] = value
-	return result[:]
+	return listOf(nvp, name, floatIn[float64](nvp))
 }
 
 // Decimal returns the name value as shopspring.Decimal. The second result returns the existence.
+// Strings are parsed with the NumberFormat of the name values.
 func (nvp *NameValues) Decimal(name string) (ssd.Decimal, bool) {
-	if !nvp.prepared {
-		nvp.prepare()
-	}
-	var (
-		exists bool
-		tmp    any
-		val    ssd.Decimal
-		err    error
-	)
-
-	name = strings.ToLower(name)
-	tmp, exists = nvp.Pair[name]
+	tmp, exists := nvp.lookup(name, "decimal")
 	if !exists {
-		return val, exists
-	}
-	// Try getting if this is really a decimal
-	// Then try as a string again
-	switch t := tmp.(type) {
-	case string:
-		t = strings.ReplaceAll(t, ",", "")
-		t = strings.ReplaceAll(t, " ", "")
-		val, err = ssd.NewFromString(t)
-		if err != nil {
-			return val, exists
-		}
-	case int:
-		val = ssd.NewFromInt(int64(t))
-		return val, exists
-	case int64:
-		val = ssd.NewFromInt(t)
-		return val, exists
-	case float32:
-		val = ssd.NewFromFloat(float64(t))
-		return val, exists
-	case float64:
-		val = ssd.NewFromFloat(t)
-		return val, exists
-	case ssd.Decimal:
-		val = t
-		return val, exists
+		return ssd.Decimal{}, exists
 	}
-
+	val, _ := toDecimalIn(nvp, tmp)
 	return val, exists
 }
 
 // Decimals returns the values as a decimal array
 // If the name does not exist, this function will return an empty decimal array
+// Elements that cannot be converted are zero. Use GetList to get the element errors.
 func (nvp *NameValues) Decimals(name string) []ssd.Decimal {
-	value, exists := nvp.Decimal(name)
-	if !exists {
-		return []ssd.Decimal{}
-	}
-	var result [1]ssd.Decimal
-	result[0] = value
-	return result[:]
+	return listOf(nvp, name, decimalIn(nvp))
 }
 
 // **************************************************************
@@ -526,6 +427,11 @@ func (nvp *NameValues) Interpolate(base string) (string, []interface{}) {
 	return Interpolate(base, *nvp)
 }
 
+// InterpolateStrict interpolates string like Interpolate, but returns an error if a secret would be inlined
+func (nvp *NameValues) InterpolateStrict(base string) (string, []interface{}, error) {
+	return InterpolateStrict(base, *nvp)
+}
+
 // SortByKey sort name values by key order array
 func (nvp *NameValues) SortByKey(keyOrder *[]string) NameValues {
 	return SortByKey(nvp, keyOrder)
@@ -535,8 +441,22 @@ func (nvp *NameValues) SortByKey(keyOrder *[]string) NameValues {
 //   Miscellaneous functions
 // **************************************************************
 
-// Interpolate interpolates string with the name value pairs
+// Interpolate interpolates string with the name value pairs.
+//
+// Secrets are written as Redacted unless AllowSecrets is set.
+// Use InterpolateStrict to get an error instead.
 func Interpolate(base string, nv NameValues) (string, []any) {
+	nstr, vals, _ := interpolate(base, nv, false)
+	return nstr, vals
+}
+
+// InterpolateStrict interpolates string with the name value pairs like Interpolate, but
+// returns an error wrapping ErrSecret if a secret would be inlined and AllowSecrets is not set.
+func InterpolateStrict(base string, nv NameValues) (string, []any, error) {
+	return interpolate(base, nv, true)
+}
+
+func interpolate(base string, nv NameValues, strict bool) (string, []any, error) {
 	var (
 		val  any
 		sval string
@@ -552,6 +472,11 @@ func Interpolate(base string, nv NameValues) (string, []any) {
 		for n, v := range nv.Pair {
 			if strings.EqualFold(match, `${`+n+`}`) {
 				sval = anyToStr(v)
+				if nv.AllowSecrets {
+					sval = anyToStr(reveal(v))
+				} else if _, ok := deref(v).(Secret); ok && strict {
+					return "", nil, fmt.Errorf("%s: %w", match, secretError())
+				}
 				val = v
 				break
 			}
@@ -559,7 +484,7 @@ func Interpolate(base string, nv NameValues) (string, []any) {
 		nstr = strings.Replace(nstr, match, sval, -1)
 		vals[i] = val //a string 0 would cater to both string and number columns
 	}
-	return nstr, vals
+	return nstr, vals, nil
 }
 
 // ToInterfaceArray converts name values to interface array
@@ -596,7 +521,7 @@ func SortByKey(values *NameValues, keyOrder *[]string) NameValues {
 	return ret
 }
 
-func getZero[T constraints.Ordered | bool]() T {
+func getZero[T constraints.Ordered | bool | time.Time]() T {
 	var r T
 	return r
 }
@@ -642,6 +567,12 @@ func anyToStr(value interface{}) string {
 		}
 	case time.Time:
 		b = "'" + t.Format(time.RFC3339) + "'"
+	case time.Duration:
+		b = t.String()
+	case ssd.Decimal:
+		b = t.String()
+	case Secret:
+		b = Redacted
 	case *string:
 		if t == nil {
 			return ""
@@ -718,6 +649,13 @@ func anyToStr(value interface{}) string {
 		}
 		tm := *t
 		b = "'" + tm.Format(time.RFC3339) + "'"
+	case *ssd.Decimal:
+		if t == nil {
+			return "0"
+		}
+		b = t.String()
+	case *Secret:
+		b = Redacted
 	}
 
 	return b
@@ -0,0 +1,235 @@
+package namevalue
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	ssd "github.com/shopspring/decimal"
+	"golang.org/x/exp/constraints"
+)
+
+// NumberFormat describes how numbers are written in numeric strings
+type NumberFormat struct {
+	Grouping rune     // Digit grouping separator, such as ',' in 1,234.56
+	Decimal  rune     // Decimal separator, such as '.' in 1,234.56
+	Currency []string // Currency symbols and codes removed before parsing. Defaults to CurrencySymbols
+	// Strict rejects grouping separators that are not followed by groups of three digits,
+	// more than one decimal separator, and grouping separators after the decimal separator.
+	// Such input is usually written in another format.
+	Strict bool
+}
+
+// Number formats of common locales
+var (
+	NumberFormatEN = &NumberFormat{Grouping: ',', Decimal: '.'}  // 1,234.56
+	NumberFormatDE = &NumberFormat{Grouping: '.', Decimal: ','}  // 1.234,56
+	NumberFormatFR = &NumberFormat{Grouping: ' ', Decimal: ','}  // 1 234,56
+	NumberFormatCH = &NumberFormat{Grouping: '\'', Decimal: '.'} // 1'234.56
+)
+
+// CurrencySymbols are the currency symbols and codes removed from numbers by default
+var CurrencySymbols = []string{
+	"$", "€", "£", "¥", "₱", "₹", "₩", "₽", "₺", "₫", "฿", "R$", "CHF", "Fr.", "kr",
+}
+
+// ErrNumberFormat is returned when a numeric string does not follow the number format
+var ErrNumberFormat = errors.New("invalid number format")
+
+// ParseNumber parses a numeric string written in the number format.
+//
+// Currency symbols are removed, a trailing percent sign divides the number by 100,
+// and numbers in parentheses, as written in accounting, are negative.
+// A nil number format parses like NumberFormatEN.
+func (nf *NumberFormat) ParseNumber(s string) (ssd.Decimal, error) {
+	if nf == nil {
+		nf = NumberFormatEN
+	}
+	orig := s
+	fail := func(reason string) (ssd.Decimal, error) {
+		return ssd.Decimal{}, fmt.Errorf("%w: %q %s", ErrNumberFormat, orig, reason)
+	}
+
+	s = strings.TrimSpace(s)
+	neg := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		neg = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	percent := false
+	if strings.HasSuffix(s, "%") {
+		percent = true
+		s = strings.TrimSpace(s[:len(s)-1])
+	}
+	s = nf.stripCurrency(s)
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		if neg && s[0] == '-' {
+			return fail("has two negative signs")
+		}
+		neg = neg || s[0] == '-'
+		s = strings.TrimSpace(nf.stripCurrency(s[1:]))
+	}
+	if s == "" {
+		return fail("has no digits")
+	}
+
+	var (
+		sb       strings.Builder
+		decimals int
+		group    = -1 // digits since the last grouping separator, -1 if none seen
+		lead     = 0  // digits before the first grouping separator
+		exponent bool
+	)
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			sb.WriteRune(r)
+			if decimals == 0 {
+				if group >= 0 {
+					group++
+				} else {
+					lead++
+				}
+			}
+		case r == nf.Decimal:
+			decimals++
+			if decimals > 1 && nf.Strict {
+				return fail("has more than one decimal separator")
+			}
+			if group >= 0 && group != 3 && nf.Strict {
+				return fail("has a misplaced grouping separator")
+			}
+			sb.WriteRune('.')
+		case r == nf.Grouping || isSpaceGrouping(r):
+			if !nf.Strict {
+				continue
+			}
+			if decimals > 0 || exponent {
+				return fail("has a grouping separator after the decimal separator")
+			}
+			if (group < 0 && (lead == 0 || lead > 3)) || (group >= 0 && group != 3) {
+				return fail("has a misplaced grouping separator")
+			}
+			group = 0
+		case (r == 'e' || r == 'E') && !exponent && sb.Len() > 0:
+			exponent = true
+			sb.WriteRune('e')
+		case (r == '-' || r == '+') && exponent && strings.HasSuffix(sb.String(), "e"):
+			sb.WriteRune(r)
+		default:
+			return fail(fmt.Sprintf("has an unexpected character %q", r))
+		}
+	}
+	if nf.Strict && decimals == 0 && group >= 0 && group != 3 {
+		return fail("has a misplaced grouping separator")
+	}
+
+	d, err := ssd.NewFromString(sb.String())
+	if err != nil {
+		return fail("is not a number")
+	}
+	if percent {
+		d = d.Shift(-2)
+	}
+	if neg {
+		d = d.Neg()
+	}
+	return d, nil
+}
+
+// stripCurrency removes a currency symbol or code at the start or end of s.
+// The longest matching symbol is removed, so R$ is removed rather than $.
+func (nf *NumberFormat) stripCurrency(s string) string {
+	symbols := nf.Currency
+	if symbols == nil {
+		symbols = CurrencySymbols
+	}
+	var prefix, suffix string
+	for _, c := range symbols {
+		if strings.HasPrefix(s, c) && len(c) > len(prefix) {
+			prefix = c
+		}
+		if strings.HasSuffix(s, c) && len(c) > len(suffix) {
+			suffix = c
+		}
+	}
+	if prefix != "" {
+		return strings.TrimSpace(s[len(prefix):])
+	}
+	if suffix != "" {
+		return strings.TrimSpace(s[:len(s)-len(suffix)])
+	}
+	return s
+}
+
+// isSpaceGrouping reports whether r is a space that may group digits in any format
+func isSpaceGrouping(r rune) bool {
+	return r == ' ' || r == '\u00a0' || r == '\u202f'
+}
+
+// toDecimalIn converts a value to a decimal, parsing strings with the number format
+func toDecimalIn(nvp *NameValues, v any) (ssd.Decimal, error) {
+	if s, ok := v.(string); ok {
+		return nvp.NumberFormat.ParseNumber(s)
+	}
+	return toDecimal(v)
+}
+
+// toIntegerIn converts a value to an integer, parsing formatted strings with the number format
+func toIntegerIn[T constraints.Integer](nvp *NameValues, v any) (T, error) {
+	r, err := toInteger[T](v)
+	s, ok := v.(string)
+	if err == nil || !ok {
+		return r, err
+	}
+	d, nerr := nvp.NumberFormat.ParseNumber(s)
+	if nerr != nil {
+		return r, err
+	}
+	if !d.IsInteger() {
+		return 0, convertError(v, KindInt)
+	}
+	return toInteger[T](d)
+}
+
+// toFloatIn converts a value to a float, parsing strings with the number format.
+// NaN and Inf are accepted as strconv.ParseFloat accepts them.
+func toFloatIn[T constraints.Float](nvp *NameValues, v any) (T, error) {
+	if s, ok := v.(string); ok {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			return T(f), nil
+		}
+		d, err := nvp.NumberFormat.ParseNumber(s)
+		if err != nil {
+			return 0, err
+		}
+		return toFloat[T](d)
+	}
+	return toFloat[T](v)
+}
+
+// integerIn returns a conversion to an integer that parses formatted strings with the
+// number format, for the elements of lists
+func integerIn[T constraints.Integer](nvp *NameValues) func(any) (T, error) {
+	return func(v any) (T, error) {
+		return toIntegerIn[T](nvp, v)
+	}
+}
+
+// floatIn returns a conversion to a float that parses strings with the number format,
+// for the elements of lists
+func floatIn[T constraints.Float](nvp *NameValues) func(any) (T, error) {
+	return func(v any) (T, error) {
+		return toFloatIn[T](nvp, v)
+	}
+}
+
+// decimalIn returns a conversion to a decimal that parses strings with the number format,
+// for the elements of lists
+func decimalIn(nvp *NameValues) func(any) (ssd.Decimal, error) {
+	return func(v any) (ssd.Decimal, error) {
+		return toDecimalIn(nvp, v)
+	}
+}
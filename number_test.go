@@ -0,0 +1,116 @@
+package namevalue
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		nf       *NumberFormat
+		in       string
+		expected string
+	}{
+		{nil, "1,234.56", "1234.56"},
+		{nil, "$ 1,234.56", "1234.56"},
+		{nil, "(123.45)", "-123.45"},
+		{nil, "-$12", "-12"},
+		{nil, "12.5%", "0.125"},
+		{nil, "1e3", "1000"},
+		{NumberFormatDE, "1.234,56", "1234.56"},
+		{NumberFormatDE, "1.234,56 €", "1234.56"},
+		{NumberFormatDE, "-0,5", "-0.5"},
+		{NumberFormatFR, "1 234,56", "1234.56"},
+		{NumberFormatCH, "CHF 1'234.50", "1234.5"},
+		{NumberFormatDE, "1.234,56 R$", "1234.56"},
+		{&NumberFormat{Grouping: '.', Decimal: ',', Currency: []string{"$", "R$"}}, "1.234,56R$", "1234.56"},
+		{&NumberFormat{Grouping: ',', Decimal: '.', Strict: true}, "1,234,567.89", "1234567.89"},
+	}
+	for _, tt := range tests {
+		d, err := tt.nf.ParseNumber(tt.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.in, err)
+			continue
+		}
+		if !d.Equal(ssd.RequireFromString(tt.expected)) {
+			t.Errorf("%q: expected %s, got %s", tt.in, tt.expected, d)
+		}
+	}
+
+	strict := &NumberFormat{Grouping: ',', Decimal: '.', Strict: true}
+	for _, in := range []string{"1.234,56", "12,34", "1,2345", "1.2.3", "1,234.5,6", "12abc", "", "(-5)"} {
+		if _, err := strict.ParseNumber(in); !errors.Is(err, ErrNumberFormat) {
+			t.Errorf("%q: expected ErrNumberFormat, got %v", in, err)
+		}
+	}
+}
+
+func TestNVNumberFormat(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"price": "1.234,56",
+			"count": "1.000",
+			"rate":  "12,5%",
+			"hex":   "0x10",
+			"nan":   "NaN",
+			"inf":   "-Inf",
+		},
+		NumberFormat: NumberFormatDE,
+	}
+	if v, _ := nvs.Decimal("price"); !v.Equal(ssd.RequireFromString("1234.56")) {
+		t.Errorf("price: unexpected %s", v)
+	}
+	if v, _ := nvs.Float64("price"); v != 1234.56 {
+		t.Errorf("price: unexpected %v", v)
+	}
+	if v, _ := nvs.Int("count"); v != 1000 {
+		t.Errorf("count: unexpected %d", v)
+	}
+	if v, _ := nvs.Float64("rate"); v != 0.125 {
+		t.Errorf("rate: unexpected %v", v)
+	}
+	if v, _ := nvs.Int("hex"); v != 16 {
+		t.Errorf("hex: unexpected %d", v)
+	}
+	if v, _ := nvs.Float64("nan"); !math.IsNaN(v) {
+		t.Errorf("nan: unexpected %v", v)
+	}
+	if v, _ := nvs.Float64("inf"); !math.IsInf(v, -1) {
+		t.Errorf("inf: unexpected %v", v)
+	}
+	if _, err := GetInteger[int](nvs, "price"); err == nil {
+		t.Errorf("price: expected an error for a fractional int")
+	}
+}
+
+func TestNVNumberFormatLists(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"prices": "1.234,5;2,5",
+			"counts": "1.000;2.000",
+		},
+		NumberFormat:  NumberFormatDE,
+		ListSeparator: ';',
+	}
+	if v := nvs.Decimals("prices"); len(v) != 2 || !v[0].Equal(ssd.RequireFromString("1234.5")) || !v[1].Equal(ssd.RequireFromString("2.5")) {
+		t.Errorf("Decimals: unexpected %v", v)
+	}
+	if v := nvs.Float64s("prices"); len(v) != 2 || v[0] != 1234.5 || v[1] != 2.5 {
+		t.Errorf("Float64s: unexpected %v", v)
+	}
+	if v := nvs.Float32s("prices"); len(v) != 2 || v[0] != 1234.5 || v[1] != 2.5 {
+		t.Errorf("Float32s: unexpected %v", v)
+	}
+	if v := nvs.Ints("counts"); len(v) != 2 || v[0] != 1000 || v[1] != 2000 {
+		t.Errorf("Ints: unexpected %v", v)
+	}
+	if v := nvs.Int64s("counts"); len(v) != 2 || v[0] != 1000 || v[1] != 2000 {
+		t.Errorf("Int64s: unexpected %v", v)
+	}
+	if v := nvs.Uint16s("counts"); len(v) != 2 || v[0] != 1000 || v[1] != 2000 {
+		t.Errorf("Uint16s: unexpected %v", v)
+	}
+}
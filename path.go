@@ -0,0 +1,181 @@
+package namevalue
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Sub returns the nested name values of a name whose value is a map or NameValues.
+// The name may be a path. The result shares the options of the name values, but not
+// their access tracking. Its names are a copy folded to lower case, so reading the
+// result does not change the keys of the nested map.
+//
+// This function returns empty name values if the name does not exist or is not a map.
+func (nvp *NameValues) Sub(name string) NameValues {
+	v, _ := nvp.lookup(name, "map")
+	m := asMap(v)
+	sub := *nvp
+	sub.Pair = make(map[string]any, len(m))
+	for k, e := range m {
+		sub.Pair[strings.ToLower(k)] = e
+	}
+	sub.prepared = true
+	sub.tracker = nil
+	return sub
+}
+
+// Set sets the value of a name. The name may be a path such as db.primary.host or a JSON
+// pointer, and missing maps and slices on the way are created as with SetPointer.
+// The value is encrypted if the name is one of the EncryptNames.
+//
+// A name that exists as a top-level name is set as is, even if it contains dots.
+func (nvp *NameValues) Set(name string, v any) error {
+	if strings.HasPrefix(name, "/") {
+		return nvp.SetPointer(name, v)
+	}
+	if !nvp.prepared {
+		nvp.prepare()
+	}
+	segs, ok := nameSegments(nvp.Pair, name)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidPointer, name)
+	}
+	return nvp.setSegments(name, segs, v)
+}
+
+// nameSegments splits a name, path or JSON pointer into segments. A name that exists
+// as a top-level name in root is a single segment, even if it contains dots.
+func nameSegments(root map[string]any, name string) ([]string, bool) {
+	var (
+		segs []string
+		ok   bool
+	)
+	switch _, exists := root[strings.ToLower(name)]; {
+	case strings.HasPrefix(name, "/"):
+		var err error
+		segs, err = parsePointer(name)
+		ok = err == nil
+	case exists || !strings.ContainsAny(name, ".["):
+		segs, ok = []string{name}, true
+	default:
+		segs, ok = parsePath(name, ".")
+	}
+	if ok && len(segs) > 0 {
+		segs[0] = strings.ToLower(segs[0])
+	}
+	return segs, ok
+}
+
+// resolvePath walks a path such as db.primary.host or servers[2].port through nested
+// maps and slices. Map keys are matched case-insensitively. Slice indexes may be
+// written in brackets or as a numeric segment, as in servers.2.port.
+func resolvePath(root map[string]any, path string) (any, bool) {
+	segs, ok := parsePath(path, ".")
+	if !ok {
+		return nil, false
+	}
+	var cur any = root
+	for _, seg := range segs {
+		if cur, ok = child(cur, seg); !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// parsePath splits a path into its segments at sep. Bracketed indexes become separate segments.
+func parsePath(path, sep string) ([]string, bool) {
+	var segs []string
+	for _, part := range strings.Split(path, sep) {
+		key, rest, hasIndex := strings.Cut(part, "[")
+		if key == "" && (!hasIndex || len(segs) == 0) {
+			return nil, false
+		}
+		if key != "" {
+			segs = append(segs, key)
+		}
+		for hasIndex {
+			var idx string
+			if idx, rest, hasIndex = strings.Cut(rest, "]"); !hasIndex {
+				return nil, false
+			}
+			segs = append(segs, idx)
+			if rest == "" {
+				break
+			}
+			if rest[0] != '[' {
+				return nil, false
+			}
+			rest = rest[1:]
+		}
+	}
+	return segs, true
+}
+
+// child returns the element of a map or slice value named by a path segment
+func child(v any, seg string) (any, bool) {
+	v = deref(v)
+	switch t := v.(type) {
+	case map[string]any:
+		return mapValue(t, seg)
+	case NameValues:
+		return mapValue(t.Pair, seg)
+	case []any:
+		i, err := strconv.Atoi(seg)
+		if err != nil || i < 0 || i >= len(t) {
+			return nil, false
+		}
+		return t[i], true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if m := asMap(v); m != nil {
+			return mapValue(m, seg)
+		}
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(seg)
+		if err != nil || i < 0 || i >= rv.Len() {
+			return nil, false
+		}
+		return rv.Index(i).Interface(), true
+	}
+	return nil, false
+}
+
+// mapValue gets a value from a map, matching the key case-insensitively
+func mapValue(m map[string]any, key string) (any, bool) {
+	if v, ok := m[key]; ok {
+		return v, ok
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// asMap returns a value as map[string]any. Other maps with string keys are copied.
+// It returns nil if the value is not such a map.
+func asMap(v any) map[string]any {
+	v = deref(v)
+	switch t := v.(type) {
+	case map[string]any:
+		return t
+	case NameValues:
+		return t.Pair
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+	m := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return m
+}
@@ -0,0 +1,85 @@
+package namevalue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNVPaths(t *testing.T) {
+	nvs, err := FromJSON([]byte(`{
+		"DB": {"Primary": {"Host": "db1", "Port": 5432}},
+		"servers": [{"port": 80}, {"port": 443}, {"Port": "8080"}],
+		"matrix": [[1, 2], [3, 4]],
+		"dotted.key": "exact"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nvs.Pair["typed"] = map[string]string{"Name": "Zaldy"}
+
+	if v, _ := nvs.String("db.primary.host"); v != "db1" {
+		t.Errorf("db.primary.host: unexpected %q", v)
+	}
+	if v, _ := nvs.Int("servers[2].port"); v != 8080 {
+		t.Errorf("servers[2].port: unexpected %d", v)
+	}
+	if v, _ := nvs.Int("servers.1.port"); v != 443 {
+		t.Errorf("servers.1.port: unexpected %d", v)
+	}
+	if v := Get[int](nvs, "matrix[1][0]"); v != 3 {
+		t.Errorf("matrix[1][0]: unexpected %d", v)
+	}
+	if v, _ := nvs.String("typed.name"); v != "Zaldy" {
+		t.Errorf("typed.name: unexpected %q", v)
+	}
+	if v, _ := nvs.String("Dotted.Key"); v != "exact" {
+		t.Errorf("dotted.key: unexpected %q", v)
+	}
+	if v := nvs.Ints("matrix[0]"); !reflect.DeepEqual(v, []int{1, 2}) {
+		t.Errorf("matrix[0]: unexpected %v", v)
+	}
+	for _, p := range []string{"db.primary.port", "SERVERS[0]", "matrix[1][1]"} {
+		if !nvs.Exists(p) {
+			t.Errorf("%s: expected to exist", p)
+		}
+	}
+	for _, p := range []string{"db.replica.host", "servers[3].port", "servers[x]", "matrix[0", "db..primary", "[0]"} {
+		if nvs.Exists(p) {
+			t.Errorf("%s: expected not to exist", p)
+		}
+	}
+}
+
+func TestNVSub(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"db": map[string]any{
+				"Primary": map[string]any{"Host": "db1", "port": "5432"},
+			},
+			"name": "Zaldy",
+		},
+		ListSeparator: ';',
+	}
+	primary := nvs.Sub("DB.primary")
+	if v, _ := primary.String("host"); v != "db1" {
+		t.Errorf("host: unexpected %q", v)
+	}
+	if v, _ := primary.Int("PORT"); v != 5432 {
+		t.Errorf("port: unexpected %d", v)
+	}
+	if primary.ListSeparator != ';' {
+		t.Errorf("expected the options to be shared")
+	}
+	if _, ok := nvs.Pair["db"].(map[string]any)["Primary"].(map[string]any)["Host"]; !ok {
+		t.Errorf("expected reading a sub not to fold the keys of the parent")
+	}
+	if sub := nvs.Sub("name"); sub.Pair == nil || len(sub.Pair) != 0 {
+		t.Errorf("name: expected empty name values, got %v", sub.Pair)
+	}
+
+	nvs.Track()
+	nvs.Sub("db")
+	if u := nvs.Unused(); !reflect.DeepEqual(u, []string{"name"}) {
+		t.Errorf("expected [name] to be unused, got %v", u)
+	}
+}
@@ -0,0 +1,96 @@
+package namevalue
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+func TestNVPointers(t *testing.T) {
+	var (
+		str   = "Zaldy"
+		num   = 48
+		big   = int64(102810281028)
+		flt   = 1028.4321
+		yes   = true
+		dec   = ssd.RequireFromString("1024.50")
+		when  = time.Date(2021, 10, 17, 0, 0, 0, 0, time.UTC)
+		nilp  *int
+		strp  = &str
+		strpp = &strp
+	)
+	nvs := NameValues{
+		Pair: map[string]any{
+			"str":   &str,
+			"num":   &num,
+			"big":   &big,
+			"flt":   &flt,
+			"yes":   &yes,
+			"dec":   &dec,
+			"when":  &when,
+			"nil":   nilp,
+			"null":  nil,
+			"deep":  strpp,
+			"nums":  []*int{&num, nilp},
+			"plain": 1,
+		},
+	}
+
+	if v, _ := nvs.String("str"); v != "Zaldy" {
+		t.Errorf("str: unexpected %q", v)
+	}
+	if v, _ := nvs.String("deep"); v != "Zaldy" {
+		t.Errorf("deep: unexpected %q", v)
+	}
+	if v, _ := nvs.Int("num"); v != 48 {
+		t.Errorf("num: unexpected %d", v)
+	}
+	if v, _ := nvs.Int64("big"); v != 102810281028 {
+		t.Errorf("big: unexpected %d", v)
+	}
+	if v, _ := nvs.Float64("flt"); v != 1028.4321 {
+		t.Errorf("flt: unexpected %v", v)
+	}
+	if v, _ := nvs.Bool("yes"); !v {
+		t.Errorf("yes: unexpected %v", v)
+	}
+	if v, _ := nvs.Decimal("dec"); !v.Equal(dec) {
+		t.Errorf("dec: unexpected %s", v)
+	}
+	if v, _ := nvs.Time("when"); !v.Equal(when) {
+		t.Errorf("when: unexpected %v", v)
+	}
+	if v := Get[int](nvs, "num"); v != 48 {
+		t.Errorf("Get num: unexpected %d", v)
+	}
+	if v := GetPtr[string](nvs, "num"); v == nil || *v != "48" {
+		t.Errorf("GetPtr num: unexpected %v", v)
+	}
+	if v := GetPtr[int](nvs, "str"); v != nil {
+		t.Errorf("GetPtr str: expected nil instead of a panic, got %v", *v)
+	}
+	if v := GetPtr[int](nvs, "nil"); v != nil {
+		t.Errorf("GetPtr nil: expected nil, got %v", *v)
+	}
+	if v, exists := nvs.Int("nil"); v != 0 || !exists {
+		t.Errorf("nil: expected an existing zero, got %d %v", v, exists)
+	}
+	if v := nvs.Ints("nums"); !reflect.DeepEqual(v, []int{48, 0}) {
+		t.Errorf("nums: unexpected %v", v)
+	}
+
+	if v, _ := nvs.Plain("str"); v != &str {
+		t.Errorf("Plain str: expected the stored pointer, got %v", v)
+	}
+	if v, _ := nvs.PtrPlain("deep"); v == nil || *v != strpp {
+		t.Errorf("PtrPlain deep: expected the stored pointer, got %v", v)
+	}
+
+	for name, expected := range map[string]bool{"nil": true, "null": true, "num": false, "plain": false, "missing": false} {
+		if nvs.IsNull(name) != expected {
+			t.Errorf("IsNull %s: expected %v", name, expected)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package namevalue
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxRangeExpansion is the largest number of values a range expression may expand to
+var MaxRangeExpansion = 100_000
+
+// Range expression errors
+var (
+	ErrReversedRange    = errors.New("range start is greater than its end")
+	ErrOverlappingRange = errors.New("ranges overlap")
+	ErrRangeTooLarge    = errors.New("range expands to too many values")
+)
+
+// IntRange returns the values as an int array, expanding range expressions.
+// Elements are split like Ints splits them, and each element is a single number
+// or a range written as 8000-8005 or 1..5 with both ends included.
+//
+// It returns ErrNotFound if the name does not exist, ErrReversedRange if a range
+// ends before it starts, ErrOverlappingRange if elements share a value and
+// ErrRangeTooLarge if the result would exceed MaxRangeExpansion values.
+func (nvp *NameValues) IntRange(name string) ([]int, error) {
+	return getRange[int](nvp, name)
+}
+
+// Int64Range returns the values as an int64 array, expanding range expressions.
+// It follows the same rules as IntRange.
+func (nvp *NameValues) Int64Range(name string) ([]int64, error) {
+	return getRange[int64](nvp, name)
+}
+
+func getRange[T int | int64](nvp *NameValues, name string) ([]T, error) {
+	tmp, exists := nvp.lookup(name, "[]"+typeName[T]())
+	if !exists {
+		return []T{}, ErrNotFound
+	}
+	elems, err := nvp.elements(tmp)
+	if err != nil {
+		return []T{}, err
+	}
+	type span struct {
+		lo, hi T
+		elem   string
+	}
+	var (
+		spans []span
+		total uint64
+	)
+	for i, e := range elems {
+		var (
+			lo, hi T
+			err    error
+		)
+		s, isStr := e.(string)
+		left, right, isRange := splitRange(s)
+		if isStr && isRange {
+			if lo, err = toInteger[T](left); err == nil {
+				hi, err = toInteger[T](right)
+			}
+			if err == nil && lo > hi {
+				err = fmt.Errorf("%w: %s", ErrReversedRange, s)
+			}
+		} else {
+			lo, err = toInteger[T](e)
+			hi = lo
+		}
+		if err != nil {
+			return []T{}, &ElementError{Index: i, Err: err}
+		}
+		// hi-lo wraps correctly in uint64 because hi >= lo, while adding one to it may not
+		if uint64(hi)-uint64(lo) >= uint64(MaxRangeExpansion)-total {
+			return []T{}, fmt.Errorf("%w: more than %d values", ErrRangeTooLarge, MaxRangeExpansion)
+		}
+		total += uint64(hi) - uint64(lo) + 1
+		spans = append(spans, span{lo, hi, anyToStr(e)})
+	}
+
+	sorted := append([]span(nil), spans...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].lo < sorted[j].lo
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].lo <= sorted[i-1].hi {
+			return []T{}, fmt.Errorf("%w: %s and %s", ErrOverlappingRange, sorted[i-1].elem, sorted[i].elem)
+		}
+	}
+
+	res := make([]T, 0, total)
+	for _, sp := range spans {
+		for v := sp.lo; ; v++ {
+			res = append(res, v)
+			if v == sp.hi {
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// splitRange splits a range expression into its start and end.
+// A leading sign belongs to the start, so -5--1 is the range from -5 to -1.
+func splitRange(s string) (string, string, bool) {
+	s = strings.TrimSpace(s)
+	if l, r, ok := strings.Cut(s, ".."); ok {
+		return strings.TrimSpace(l), strings.TrimSpace(r), true
+	}
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+	l, r, ok := strings.Cut(s, "-")
+	if !ok || strings.TrimSpace(l) == "" {
+		return "", "", false
+	}
+	return sign + strings.TrimSpace(l), strings.TrimSpace(r), true
+}
@@ -0,0 +1,53 @@
+package namevalue
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIntRange(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"ports":    "8000-8005,9000",
+			"days":     "1..5",
+			"negative": "-5--3, -1, 2",
+			"stored":   []any{"1..2", 4},
+			"reversed": "5-1",
+			"overlap":  "1-5,3",
+			"large":    "1-1000000",
+			"wrapping": "1, -9223372036854775808..9223372036854775807",
+			"invalid":  "1,x",
+		},
+	}
+
+	if v, err := nvs.IntRange("ports"); err != nil || !reflect.DeepEqual(v, []int{8000, 8001, 8002, 8003, 8004, 8005, 9000}) {
+		t.Errorf("ports: unexpected %v %v", v, err)
+	}
+	if v, err := nvs.Int64Range("days"); err != nil || !reflect.DeepEqual(v, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("days: unexpected %v %v", v, err)
+	}
+	if v, err := nvs.IntRange("negative"); err != nil || !reflect.DeepEqual(v, []int{-5, -4, -3, -1, 2}) {
+		t.Errorf("negative: unexpected %v %v", v, err)
+	}
+	if v, err := nvs.IntRange("stored"); err != nil || !reflect.DeepEqual(v, []int{1, 2, 4}) {
+		t.Errorf("stored: unexpected %v %v", v, err)
+	}
+
+	errs := map[string]error{
+		"reversed": ErrReversedRange,
+		"overlap":  ErrOverlappingRange,
+		"large":    ErrRangeTooLarge,
+		"wrapping": ErrRangeTooLarge,
+		"missing":  ErrNotFound,
+	}
+	for name, target := range errs {
+		if _, err := nvs.IntRange(name); !errors.Is(err, target) {
+			t.Errorf("%s: expected %v, got %v", name, target, err)
+		}
+	}
+	var ee *ElementError
+	if _, err := nvs.IntRange("invalid"); !errors.As(err, &ee) || ee.Index != 1 {
+		t.Errorf("invalid: expected an error for element 1, got %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package namevalue
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+type (
+	// Field declares the rules for a single name in a Schema
+	Field struct {
+		Name       string            // Name of the value. Matched case-insensitively
+		Type       Kind              // Type the value is converted to
+		Required   bool              // The value must exist and must not be nil
+		Default    any               // Value used when the name does not exist
+		Min        *float64          // Minimum numeric value
+		Max        *float64          // Maximum numeric value
+		MinLength  int               // Minimum length of a string in characters
+		MaxLength  int               // Maximum length of a string in characters. Zero means no limit
+		Pattern    *regexp.Regexp    // Pattern a string value must match
+		Enum       []any             // Allowed values, converted to the field type before comparing
+		Validators []func(any) error // Custom validators called with the converted value
+		Secret     bool              // The converted value is wrapped in a Secret. Errors do not include the value
+	}
+	// Schema is a declarative set of rules for NameValues
+	Schema struct {
+		Fields []Field
+	}
+	// FieldError is a validation error of a single field
+	FieldError struct {
+		Name string
+		Err  error
+	}
+	// ValidationErrors is the list of field errors returned by Schema.Validate and Schema.Apply
+	ValidationErrors []*FieldError
+)
+
+// Validation errors
+var (
+	ErrRequired   = errors.New("value is required")
+	ErrOutOfRange = errors.New("value is out of range")
+	ErrLength     = errors.New("length is out of range")
+	ErrPattern    = errors.New("value does not match the pattern")
+	ErrEnum       = errors.New("value is not one of the allowed values")
+)
+
+// NewSchema creates a schema from fields
+func NewSchema(fields ...Field) *Schema {
+	return &Schema{
+		Fields: fields,
+	}
+}
+
+// Error returns the field name and its error
+func (fe *FieldError) Error() string {
+	return fe.Name + ": " + fe.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (fe *FieldError) Unwrap() error {
+	return fe.Err
+}
+
+// Error returns all field errors separated by semicolons
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i, fe := range ve {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the field errors so that errors.Is and errors.As can inspect them
+func (ve ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(ve))
+	for i, fe := range ve {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// Validate checks the name values against the schema.
+// It returns ValidationErrors listing every field that failed, or nil.
+func (s *Schema) Validate(nvs NameValues) error {
+	_, err := s.Apply(nvs)
+	return err
+}
+
+// Apply returns a normalized copy of the name values. Missing values are filled with
+// their defaults and declared values are converted to their types. Values of secret
+// fields are wrapped in a Secret. Names that are not declared in the schema are copied as is.
+// The copy keeps the options of the name values. Encrypted values are decrypted with the
+// Keyring before they are checked and encrypted again, and values of the EncryptNames are
+// encrypted like Set encrypts them.
+//
+// The copy is returned even if validation fails, together with ValidationErrors.
+func (s *Schema) Apply(nvs NameValues) (NameValues, error) {
+	if !nvs.prepared {
+		nvs.prepare()
+	}
+	res := nvs
+	res.tracker = nil
+	res.Pair = make(map[string]any, len(nvs.Pair))
+	for n, v := range nvs.Pair {
+		res.Pair[n] = v
+	}
+	var errs ValidationErrors
+	for i := range s.Fields {
+		f := &s.Fields[i]
+		name := strings.ToLower(f.Name)
+		v, exists := res.Pair[name]
+		if !exists && f.Default != nil {
+			v, exists = f.Default, true
+		}
+		if !exists || v == nil {
+			if f.Required {
+				errs = append(errs, &FieldError{Name: f.Name, Err: ErrRequired})
+			}
+			continue
+		}
+		encrypted := false
+		if s, ok := deref(v).(string); ok && nvs.Keyring != nil && IsEncrypted(s) {
+			dv, err := nvs.Keyring.Decrypt(s)
+			if err != nil {
+				errs = append(errs, &FieldError{Name: f.Name, Err: err})
+				continue
+			}
+			v, encrypted = dv, true
+		}
+		cv, err := f.check(reveal(v), nvs.location())
+		if err != nil {
+			if f.Secret {
+				err = redactError(err)
+			}
+			errs = append(errs, &FieldError{Name: f.Name, Err: err})
+			continue
+		}
+		if f.Secret {
+			cv = NewSecret(cv)
+		}
+		if encrypted {
+			if cv, err = nvs.Keyring.Encrypt(cv); err != nil {
+				errs = append(errs, &FieldError{Name: f.Name, Err: err})
+				continue
+			}
+		}
+		res.Pair[name] = cv
+	}
+	if err := res.sealNames(res.Pair); err != nil {
+		return res, err
+	}
+	if len(errs) > 0 {
+		return res, errs
+	}
+	return res, nil
+}
+
+// check converts the value to the field type and validates it. Times without a time zone are read in loc.
+func (f *Field) check(v any, loc *time.Location) (any, error) {
+	cv, err := convert(f.Type, v, loc)
+	if err != nil {
+		return nil, err
+	}
+	if f.Min != nil || f.Max != nil {
+		if err := f.checkRange(cv); err != nil {
+			return nil, err
+		}
+	}
+	if s, ok := cv.(string); ok {
+		n := utf8.RuneCountInString(s)
+		if n < f.MinLength || (f.MaxLength > 0 && n > f.MaxLength) {
+			return nil, fmt.Errorf("%w: %d characters", ErrLength, n)
+		}
+		if f.Pattern != nil && !f.Pattern.MatchString(s) {
+			return nil, fmt.Errorf("%w %s", ErrPattern, f.Pattern)
+		}
+	}
+	if len(f.Enum) > 0 {
+		found := false
+		for _, e := range f.Enum {
+			ce, err := convert(f.Type, e, loc)
+			if err == nil && equalValue(ce, cv) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrEnum
+		}
+	}
+	for _, fn := range f.Validators {
+		if err := fn(cv); err != nil {
+			return nil, err
+		}
+	}
+	return cv, nil
+}
+
+// checkRange checks a number against Min and Max. Values that are not numbers are not checked.
+func (f *Field) checkRange(v any) error {
+	if _, ok := v.(string); ok {
+		return nil
+	}
+	n, err := toDecimal(v)
+	if err != nil {
+		return nil
+	}
+	if f.Min != nil && n.LessThan(ssd.NewFromFloat(*f.Min)) {
+		return fmt.Errorf("%w: %s is less than %v", ErrOutOfRange, n, *f.Min)
+	}
+	if f.Max != nil && n.GreaterThan(ssd.NewFromFloat(*f.Max)) {
+		return fmt.Errorf("%w: %s is greater than %v", ErrOutOfRange, n, *f.Max)
+	}
+	return nil
+}
+
+// redactError returns the validation error that err wraps without the details,
+// which may include the value
+func redactError(err error) error {
+	for _, e := range []error{ErrOutOfRange, ErrLength, ErrPattern, ErrEnum} {
+		if errors.Is(err, e) {
+			return e
+		}
+	}
+	return ErrInvalidType
+}
+
+// equalValue compares two converted values
+func equalValue(a, b any) bool {
+	if da, ok := a.(ssd.Decimal); ok {
+		db, ok := b.(ssd.Decimal)
+		return ok && da.Equal(db)
+	}
+	return reflect.DeepEqual(a, b)
+}
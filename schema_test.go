@@ -0,0 +1,105 @@
+package namevalue
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+func TestSchemaApply(t *testing.T) {
+	minAge, maxAge := 18.0, 120.0
+	schema := NewSchema(
+		Field{Name: "Name", Type: KindString, Required: true, MaxLength: 10},
+		Field{Name: "age", Type: KindInt, Min: &minAge, Max: &maxAge},
+		Field{Name: "active", Type: KindBool, Default: "yes"},
+		Field{Name: "balance", Type: KindDecimal, Default: 0},
+		Field{Name: "role", Type: KindString, Enum: []any{"admin", "user"}, Default: "user"},
+	)
+
+	out, err := schema.Apply(NameValues{
+		Pair: map[string]any{
+			"NAME":    "Zaldy",
+			"age":     "48",
+			"balance": "1,024.50",
+			"extra":   true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := out.Plain("age"); v != 48 {
+		t.Errorf("age: expected int 48, got %T %v", v, v)
+	}
+	if v, _ := out.Plain("active"); v != true {
+		t.Errorf("active: expected default true, got %v", v)
+	}
+	if v, _ := out.Plain("balance"); !v.(ssd.Decimal).Equal(ssd.RequireFromString("1024.5")) {
+		t.Errorf("balance: expected 1024.5, got %v", v)
+	}
+	if v, _ := out.String("role"); v != "user" {
+		t.Errorf("role: expected default user, got %s", v)
+	}
+	if v, _ := out.Plain("extra"); v != true {
+		t.Errorf("extra: expected undeclared value to be copied")
+	}
+
+	manila := time.FixedZone("PHT", 8*3600)
+	out, err = NewSchema(Field{Name: "at", Type: KindTime}).Apply(NameValues{
+		Pair:     map[string]any{"at": "2024-01-02 03:04:05"},
+		Location: manila,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := out.Plain("at"); !v.(time.Time).Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, manila)) {
+		t.Errorf("at: expected the time in the Location of the name values, got %v", v)
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	minAge := 18.0
+	schema := NewSchema(
+		Field{Name: "name", Type: KindString, Required: true},
+		Field{Name: "age", Type: KindInt, Min: &minAge},
+		Field{Name: "code", Type: KindString, Pattern: regexp.MustCompile(`^[A-Z]{3}$`)},
+		Field{Name: "role", Type: KindString, Enum: []any{"admin", "user"}},
+		Field{Name: "count", Type: KindInt},
+		Field{Name: "even", Type: KindInt, Validators: []func(any) error{
+			func(v any) error {
+				if v.(int)%2 != 0 {
+					return errors.New("must be even")
+				}
+				return nil
+			},
+		}},
+	)
+
+	err := schema.Validate(NameValues{
+		Pair: map[string]any{
+			"age":   12,
+			"code":  "abc",
+			"role":  "guest",
+			"count": "many",
+			"even":  3,
+		},
+	})
+	var ve ValidationErrors
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	if len(ve) != 6 {
+		t.Errorf("expected 6 field errors, got %d: %v", len(ve), ve)
+	}
+	for _, target := range []error{ErrRequired, ErrOutOfRange, ErrPattern, ErrEnum} {
+		if !errors.Is(err, target) {
+			t.Errorf("expected %v in %v", target, err)
+		}
+	}
+
+	if err := schema.Validate(NameValues{Pair: map[string]any{"name": "Zaldy"}}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package namevalue
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Redacted is printed in place of the value of a Secret
+const Redacted = "****"
+
+// ErrSecret is returned when a Secret would be written where its value is stored, such as JSON or CSV
+var ErrSecret = errors.New("value is a secret")
+
+// Secret is a value that redacts itself when it is printed or logged.
+// fmt verbs, String, slog and the JSON and text encoders print Redacted, so log encoders
+// never write the value. The functions that store name values, such as ToJSON, Value,
+// WriteCSV, ToValues and the patches of Changes, return an error wrapping ErrSecret
+// instead of storing Redacted. Use NameValues.Reveal to store secrets deliberately.
+//
+// The typed getters of NameValues return the value of a secret as if it was not wrapped.
+// Plain and Pointer return the Secret itself, and Reveal returns its value.
+type Secret struct {
+	value any
+}
+
+// NewSecret wraps a value in a Secret
+func NewSecret(v any) Secret {
+	return Secret{
+		value: v,
+	}
+}
+
+// Reveal returns the value of the secret
+func (s Secret) Reveal() any {
+	return s.value
+}
+
+// String returns Redacted
+func (s Secret) String() string {
+	return Redacted
+}
+
+// Format prints Redacted for every verb
+func (s Secret) Format(f fmt.State, verb rune) {
+	io.WriteString(f, Redacted)
+}
+
+// MarshalJSON encodes the secret as the string Redacted
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + Redacted + `"`), nil
+}
+
+// MarshalText encodes the secret as Redacted
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// LogValue implements the slog.LogValuer interface
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// Value implements the driver.Valuer interface so that the value of a secret can be
+// passed as a query argument
+func (s Secret) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(deref(s.value))
+}
+
+// Reveal returns a copy of the name values with every secret, including nested ones,
+// replaced by its value. Use it to store secrets deliberately, as in ToJSON(nvs.Reveal()).
+func (nvp *NameValues) Reveal() NameValues {
+	res := *nvp
+	res.tracker = nil
+	res.Pair = make(map[string]any, len(nvp.Pair))
+	for k, v := range nvp.Pair {
+		res.Pair[k] = revealAll(v)
+	}
+	return res
+}
+
+// revealAll returns a copy of a value with the secrets in it replaced by their values
+func revealAll(v any) any {
+	v = reveal(v)
+	if m := asMap(v); m != nil {
+		c := make(map[string]any, len(m))
+		for k, e := range m {
+			c[k] = revealAll(e)
+		}
+		return c
+	}
+	if _, ok := v.([]byte); !ok {
+		if s, ok := asSlice(v); ok {
+			c := make([]any, len(s))
+			for i, e := range s {
+				c[i] = revealAll(e)
+			}
+			return c
+		}
+	}
+	return v
+}
+
+// storedSecret replaces a Secret in values that are stored as JSON so that encoding
+// fails instead of storing Redacted
+type storedSecret struct{}
+
+// MarshalJSON returns an error wrapping ErrSecret
+func (storedSecret) MarshalJSON() ([]byte, error) {
+	return nil, secretError()
+}
+
+// secretError is the error of writing a secret
+func secretError() error {
+	return fmt.Errorf("%w: reveal it before storing it", ErrSecret)
+}
+
+// reveal dereferences a value and unwraps it if it is a Secret
+func reveal(v any) any {
+	v = deref(v)
+	if s, ok := v.(Secret); ok {
+		return deref(s.value)
+	}
+	return v
+}
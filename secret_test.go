@@ -0,0 +1,148 @@
+package namevalue
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	s := NewSecret("hunter2")
+	for _, v := range []string{
+		fmt.Sprint(s),
+		fmt.Sprintf("%v %+v %#v %s %q %d", s, s, s, s, s, s),
+		s.String(),
+		anyToStr(s),
+		anyToStr(&s),
+	} {
+		if strings.Contains(v, "hunter2") {
+			t.Errorf("expected the secret to be redacted, got %s", v)
+		}
+	}
+	if b, err := json.Marshal(map[string]any{"password": s}); err != nil || string(b) != `{"password":"****"}` {
+		t.Errorf("expected JSON encoding to redact the secret, got %s %v", b, err)
+	}
+	if b, err := s.MarshalText(); err != nil || string(b) != Redacted {
+		t.Errorf("expected text encoding to redact the secret, got %s %v", b, err)
+	}
+	buf := bytes.Buffer{}
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("login", "creds", map[string]any{"password": s})
+	if strings.Contains(buf.String(), "hunter2") || !strings.Contains(buf.String(), `"password":"****"`) {
+		t.Errorf("unexpected JSON log %s", buf.String())
+	}
+	buf.Reset()
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("login", "password", s)
+	if strings.Contains(buf.String(), "hunter2") || !strings.Contains(buf.String(), "password=****") {
+		t.Errorf("unexpected log %s", buf.String())
+	}
+	if v, err := s.Value(); err != nil || v != "hunter2" {
+		t.Errorf("expected the driver value to be revealed, got %v %v", v, err)
+	}
+	if s.Reveal() != "hunter2" {
+		t.Errorf("expected Reveal to return the value")
+	}
+}
+
+func TestSecretGetters(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{
+		"user":     "zaldy",
+		"password": NewSecret("hunter2"),
+		"pin":      NewSecret("1234"),
+		"keys":     []any{NewSecret("a"), "b"},
+	}}
+
+	if v, _ := nvs.String("password"); v != "hunter2" {
+		t.Errorf("expected hunter2, got %q", v)
+	}
+	if v, _ := nvs.Int("pin"); v != 1234 {
+		t.Errorf("expected 1234, got %d", v)
+	}
+	if v := Get[string](nvs, "password"); v != "hunter2" {
+		t.Errorf("expected hunter2, got %q", v)
+	}
+	if v := nvs.Strings("keys"); len(v) != 2 || v[0] != "a" {
+		t.Errorf("unexpected keys %v", v)
+	}
+	if v, _ := nvs.Plain("password"); fmt.Sprint(v) != Redacted {
+		t.Errorf("expected Plain to return the secret, got %v", v)
+	}
+
+	q, args := nvs.Interpolate("${user}:${password}")
+	if q != "zaldy:****" {
+		t.Errorf("expected the secret not to be inlined, got %s", q)
+	}
+	if v, _ := args[1].(Secret); v.Reveal() != "hunter2" {
+		t.Errorf("expected the argument to be the secret, got %v", args[1])
+	}
+	if _, _, err := nvs.InterpolateStrict("${user}:${password}"); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected ErrSecret, got %v", err)
+	}
+	nvs.AllowSecrets = true
+	if q, _ := nvs.Interpolate("${user}:${password}"); q != "zaldy:hunter2" {
+		t.Errorf("expected the secret to be inlined, got %s", q)
+	}
+	if q, _, err := nvs.InterpolateStrict("${user}:${password}"); err != nil || q != "zaldy:hunter2" {
+		t.Errorf("expected the secret to be inlined, got %s %v", q, err)
+	}
+}
+
+func TestSecretStorage(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{
+		"user": "zaldy",
+		"db":   map[string]any{"password": NewSecret("hunter2")},
+	}}
+	if _, err := ToJSON(nvs); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected ToJSON to fail with ErrSecret, got %v", err)
+	}
+	if _, err := nvs.Value(); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected Value to fail with ErrSecret, got %v", err)
+	}
+	ptr := NewSecret("hunter2")
+	if _, err := ToJSON(NameValues{Pair: map[string]any{"p": &ptr}}); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected ToJSON to fail with ErrSecret for a pointer, got %v", err)
+	}
+	if _, err := (NameValue[any]{Name: "p", Value: ptr}).Valuer().Value(); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected Valuer to fail with ErrSecret, got %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, []NameValues{{Pair: map[string]any{"pin": NewSecret(1)}}}, nil); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected WriteCSV to fail with ErrSecret, got %v", err)
+	}
+	changes := Diff(NameValues{}, nvs, DiffOptions{})
+	if _, err := changes.JSONPatch(); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected JSONPatch to fail with ErrSecret, got %v", err)
+	}
+	if _, err := changes.MergePatch(); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected MergePatch to fail with ErrSecret, got %v", err)
+	}
+
+	revealed := nvs.Reveal()
+	b, err := ToJSON(revealed)
+	if err != nil || string(b) != `{"db":{"password":"hunter2"},"user":"zaldy"}` {
+		t.Errorf("unexpected revealed JSON %s %v", b, err)
+	}
+	if _, ok := nvs.Pair["db"].(map[string]any)["password"].(Secret); !ok {
+		t.Errorf("expected Reveal not to change the original values")
+	}
+}
+
+func TestSchemaSecret(t *testing.T) {
+	s := NewSchema(
+		Field{Name: "password", Type: KindString, Secret: true, MinLength: 8},
+		Field{Name: "pin", Type: KindInt, Secret: true},
+	)
+	res, err := s.Apply(NameValues{Pair: map[string]any{"password": "hunter2!", "pin": "12x4"}})
+	if !errors.Is(err, ErrInvalidType) || strings.Contains(err.Error(), "12x4") {
+		t.Errorf("expected a redacted error, got %v", err)
+	}
+	if v, ok := res.Pair["password"].(Secret); !ok || v.Reveal() != "hunter2!" {
+		t.Errorf("expected the password to be a secret, got %v", res.Pair["password"])
+	}
+	if err := s.Validate(NameValues{Pair: map[string]any{"password": NewSecret("short"), "pin": 1}}); !errors.Is(err, ErrLength) {
+		t.Errorf("expected ErrLength, got %v", err)
+	}
+}
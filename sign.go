@@ -0,0 +1,278 @@
+package namevalue
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Algorithm is the HMAC algorithm used by Sign and Verify
+type Algorithm string
+
+// Signing algorithms
+const (
+	HS256 Algorithm = "HS256"
+	HS384 Algorithm = "HS384"
+	HS512 Algorithm = "HS512"
+)
+
+// Names with a meaning in signed name values
+const (
+	SignExpires   = "exp" // Time after which the values are not valid, in Unix seconds or RFC 3339
+	SignNotBefore = "nbf" // Time before which the values are not valid, in Unix seconds or RFC 3339
+	SignKeyID     = "kid" // Id of the key that signed the values
+	SignSignature = "sig" // Signature parameter appended by SignQuery
+)
+
+// Signature errors
+var (
+	ErrSignature   = errors.New("invalid signature")
+	ErrExpired     = errors.New("signed values have expired")
+	ErrNotYetValid = errors.New("signed values are not valid yet")
+)
+
+// now returns the current time. It is replaced in tests.
+var now = time.Now
+
+// Canonical returns the canonical serialization of the name values that is signed by Sign.
+// Nested values are flattened into names joined by dots, names are folded to lower case
+// and sorted, and the names and values are query escaped and joined as name=value&name=value.
+// Numbers, booleans and times have a single text representation, so the same values
+// read back from a query string have the same serialization. Empty maps and slices are
+// written as {} and [], which differ from an empty string.
+//
+// It returns an error wrapping ErrCollision if two names flatten to the same name, such
+// as a.b and the b of a, or differ only in case, and an error wrapping ErrSecret if the
+// values have a Secret. Tokens are not encrypted, so secrets are not signed.
+func Canonical(nvs NameValues) (string, error) {
+	flat := make(map[string]string, len(nvs.Pair))
+	for k, v := range nvs.Pair {
+		if err := canonicalInto(flat, strings.ToLower(k), v); err != nil {
+			return "", err
+		}
+	}
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	sb := strings.Builder{}
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(url.QueryEscape(k))
+		sb.WriteByte('=')
+		sb.WriteString(url.QueryEscape(flat[k]))
+	}
+	return sb.String(), nil
+}
+
+// Sign signs the name values and returns a URL-safe token of the canonical serialization
+// and its signature. The token can be read back with Verify.
+//
+// The values may have an expiry time in exp, a start time in nbf and the id of the key
+// in kid, which lets Verify pick the key when keys are rotated. The values are not
+// encrypted, so anyone holding the token can read them. It returns the errors of Canonical.
+func Sign(nvs NameValues, key []byte, alg Algorithm) (string, error) {
+	payload, err := Canonical(nvs)
+	if err != nil {
+		return "", err
+	}
+	sig, err := signature(payload, key, alg)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + sig, nil
+}
+
+// SignQuery signs the name values like Sign and returns the canonical serialization as a
+// query string with the signature appended as the sig parameter. It returns an error if
+// the values already have a sig name, or the errors of Canonical.
+func SignQuery(nvs NameValues, key []byte, alg Algorithm) (string, error) {
+	if nvs.Exists(SignSignature) {
+		return "", fmt.Errorf("namevalue: %s is reserved for the signature", SignSignature)
+	}
+	payload, err := Canonical(nvs)
+	if err != nil {
+		return "", err
+	}
+	sig, err := signature(payload, key, alg)
+	if err != nil {
+		return "", err
+	}
+	if payload == "" {
+		return SignSignature + "=" + sig, nil
+	}
+	return payload + "&" + SignSignature + "=" + sig, nil
+}
+
+// Verify checks the signature of a token created by Sign and returns its name values.
+// The key is picked from keys by the kid value, or by the empty id if there is none.
+// Values read from a token are strings.
+//
+// It returns an error wrapping ErrSignature if the token is malformed, its key is unknown
+// or its signature does not match, ErrExpired if it has expired, or ErrNotYetValid if it
+// is not valid yet.
+func Verify(token string, keys map[string][]byte, alg Algorithm) (NameValues, error) {
+	enc, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return NameValues{}, fmt.Errorf("%w: malformed token", ErrSignature)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(enc)
+	if err != nil {
+		return NameValues{}, fmt.Errorf("%w: malformed token", ErrSignature)
+	}
+	values, err := url.ParseQuery(string(payload))
+	if err != nil {
+		return NameValues{}, fmt.Errorf("%w: malformed token", ErrSignature)
+	}
+	return verify(FromValues(values, FormOptions{}), sig, keys, alg)
+}
+
+// VerifyQuery checks the signature of a query string created by SignQuery, such as the
+// RawQuery of a URL, and returns its name values without the sig parameter.
+// It returns the errors of Verify.
+func VerifyQuery(query string, keys map[string][]byte, alg Algorithm) (NameValues, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return NameValues{}, fmt.Errorf("%w: malformed query", ErrSignature)
+	}
+	sigs := values[SignSignature]
+	if len(sigs) != 1 {
+		return NameValues{}, fmt.Errorf("%w: expected one %s parameter", ErrSignature, SignSignature)
+	}
+	values.Del(SignSignature)
+	return verify(FromValues(values, FormOptions{}), sigs[0], keys, alg)
+}
+
+// verify checks the signature and the validity period of name values
+func verify(nvs NameValues, sig string, keys map[string][]byte, alg Algorithm) (NameValues, error) {
+	kid, _ := nvs.String(SignKeyID)
+	key, ok := keys[kid]
+	if !ok {
+		return NameValues{}, fmt.Errorf("%w: unknown key %q", ErrSignature, kid)
+	}
+	payload, err := Canonical(nvs)
+	if err != nil {
+		return NameValues{}, fmt.Errorf("%w: %w", ErrSignature, err)
+	}
+	expected, err := signature(payload, key, alg)
+	if err != nil {
+		return NameValues{}, err
+	}
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return NameValues{}, ErrSignature
+	}
+	t := now()
+	if v, exists := nvs.Plain(SignExpires); exists {
+		exp, err := toTime(deref(v), nil, time.UTC)
+		if err != nil {
+			return NameValues{}, fmt.Errorf("%w: %s: %w", ErrExpired, SignExpires, err)
+		}
+		if !t.Before(exp) {
+			return NameValues{}, ErrExpired
+		}
+	}
+	if v, exists := nvs.Plain(SignNotBefore); exists {
+		nbf, err := toTime(deref(v), nil, time.UTC)
+		if err != nil {
+			return NameValues{}, fmt.Errorf("%w: %s: %w", ErrNotYetValid, SignNotBefore, err)
+		}
+		if t.Before(nbf) {
+			return NameValues{}, ErrNotYetValid
+		}
+	}
+	return nvs, nil
+}
+
+// signature returns the URL-safe HMAC of a payload
+func signature(payload string, key []byte, alg Algorithm) (string, error) {
+	var h func() hash.Hash
+	switch alg {
+	case HS256:
+		h = sha256.New
+	case HS384:
+		h = sha512.New384
+	case HS512:
+		h = sha512.New
+	default:
+		return "", fmt.Errorf("namevalue: unknown algorithm %q", alg)
+	}
+	if len(key) == 0 {
+		return "", errors.New("namevalue: empty signing key")
+	}
+	mac := hmac.New(h, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// canonicalInto adds the flattened names and formatted values of a value to flat
+func canonicalInto(flat map[string]string, key string, v any) error {
+	v = deref(v)
+	if _, ok := v.(Secret); ok {
+		return fmt.Errorf("%w: %s", secretError(), key)
+	}
+	if m := asMap(v); m != nil {
+		if len(m) == 0 {
+			return canonicalSet(flat, key, "{}")
+		}
+		for k, e := range m {
+			if err := canonicalInto(flat, key+"."+strings.ToLower(k), e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if _, ok := v.([]byte); !ok {
+		if s, ok := asSlice(v); ok {
+			if len(s) == 0 {
+				return canonicalSet(flat, key, "[]")
+			}
+			for i, e := range s {
+				if err := canonicalInto(flat, key+"."+strconv.Itoa(i), e); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	s, err := canonicalValue(v)
+	if err != nil {
+		return err
+	}
+	return canonicalSet(flat, key, s)
+}
+
+// canonicalSet sets a flattened name once
+func canonicalSet(flat map[string]string, key, value string) error {
+	if _, exists := flat[key]; exists {
+		return fmt.Errorf("%w: %s", ErrCollision, key)
+	}
+	flat[key] = value
+	return nil
+}
+
+// canonicalValue formats a value for Canonical
+func canonicalValue(v any) (string, error) {
+	switch t := v.(type) {
+	case float32:
+		return strconv.FormatFloat(float64(t), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(t, 'g', -1, 64), nil
+	case time.Time:
+		return t.UTC().Format(time.RFC3339Nano), nil
+	case nil:
+		return "", nil
+	}
+	return formatString(v)
+}
@@ -0,0 +1,150 @@
+package namevalue
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCanonical(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{
+		"Name":  "Zaldy Baguinon",
+		"age":   48,
+		"ratio": 0.5,
+		"db":    map[string]any{"host": "a&b"},
+		"tags":  []string{"x", "y"},
+		"none":  map[string]any{},
+		"list":  []any{},
+		"blank": "",
+		"at":    time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600)),
+	}}
+	expected := "age=48&at=2024-01-02T02%3A04%3A05Z&blank=&db.host=a%26b&list=%5B%5D&name=Zaldy+Baguinon&none=%7B%7D&ratio=0.5&tags.0=x&tags.1=y"
+	if v, err := Canonical(nvs); err != nil || v != expected {
+		t.Errorf("expected %s, got %s, %v", expected, v, err)
+	}
+
+	for _, pair := range []map[string]any{
+		{"a.b": "x", "a": map[string]any{"b": "y"}},
+		{"A": "x", "a": "y"},
+		{"a": map[string]any{"B": "x", "b": "y"}},
+	} {
+		if _, err := Canonical(NameValues{Pair: pair}); !errors.Is(err, ErrCollision) {
+			t.Errorf("%v: expected ErrCollision, got %v", pair, err)
+		}
+		if _, err := Sign(NameValues{Pair: pair}, []byte("key"), HS256); !errors.Is(err, ErrCollision) {
+			t.Errorf("%v: expected Sign to fail with ErrCollision, got %v", pair, err)
+		}
+	}
+
+	secret := NameValues{Pair: map[string]any{"db": map[string]any{"pass": NewSecret("hunter2")}}}
+	if _, err := Sign(secret, []byte("key"), HS256); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected ErrSecret, got %v", err)
+	}
+	if _, err := SignQuery(secret, []byte("key"), HS256); !errors.Is(err, ErrSecret) {
+		t.Errorf("expected ErrSecret, got %v", err)
+	}
+	revealed := secret.Reveal()
+	if _, err := Sign(revealed, []byte("key"), HS256); err != nil {
+		t.Errorf("expected revealed secrets to be signed, got %v", err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return time.Unix(1_700_000_000, 0) }
+
+	keys := map[string][]byte{"k1": []byte("old key"), "k2": []byte("new key")}
+	nvs := NameValues{Pair: map[string]any{
+		"user": "zaldy",
+		"exp":  1_700_000_060,
+		"kid":  "k2",
+	}}
+
+	for _, alg := range []Algorithm{HS256, HS384, HS512} {
+		token, err := Sign(nvs, keys["k2"], alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.ContainsAny(token, "+/=&?") {
+			t.Errorf("expected a URL-safe token, got %s", token)
+		}
+		res, err := Verify(token, keys, alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := res.String("user"); v != "zaldy" {
+			t.Errorf("expected zaldy, got %q", v)
+		}
+		if _, err := Verify(token, keys, otherAlg(alg)); !errors.Is(err, ErrSignature) {
+			t.Errorf("expected ErrSignature for another algorithm, got %v", err)
+		}
+	}
+
+	token, _ := Sign(nvs, keys["k2"], HS256)
+	payload, sig, _ := strings.Cut(token, ".")
+	tampered := payload[:len(payload)-2] + "xx." + sig
+	if _, err := Verify(tampered, keys, HS256); !errors.Is(err, ErrSignature) {
+		t.Errorf("expected ErrSignature, got %v", err)
+	}
+	if _, err := Verify(token, map[string][]byte{"k1": keys["k1"]}, HS256); !errors.Is(err, ErrSignature) {
+		t.Errorf("expected ErrSignature for an unknown key, got %v", err)
+	}
+
+	now = func() time.Time { return time.Unix(1_700_000_060, 0) }
+	if _, err := Verify(token, keys, HS256); !errors.Is(err, ErrExpired) {
+		t.Errorf("expected ErrExpired, got %v", err)
+	}
+
+	nvs.Pair["nbf"] = time.Unix(1_700_000_100, 0)
+	nvs.Pair["exp"] = time.Unix(1_700_000_200, 0)
+	token, _ = Sign(nvs, keys["k2"], HS256)
+	if _, err := Verify(token, keys, HS256); !errors.Is(err, ErrNotYetValid) {
+		t.Errorf("expected ErrNotYetValid, got %v", err)
+	}
+	now = func() time.Time { return time.Unix(1_700_000_150, 0) }
+	if _, err := Verify(token, keys, HS256); err != nil {
+		t.Errorf("expected the token to be valid, got %v", err)
+	}
+}
+
+func TestSignQuery(t *testing.T) {
+	key := []byte("secret")
+	nvs := NameValues{Pair: map[string]any{"id": 7, "Next": "/home?a=1"}}
+
+	q, err := SignQuery(nvs, key, HS256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(q, "id=7&next=%2Fhome%3Fa%3D1&sig=") {
+		t.Errorf("unexpected query %s", q)
+	}
+	res, err := VerifyQuery(q, map[string][]byte{"": key}, HS256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := res.Int("id"); v != 7 || res.Exists("sig") {
+		t.Errorf("unexpected values %v", res.Pair)
+	}
+
+	for _, bad := range []string{
+		strings.Replace(q, "id=7", "id=8", 1),
+		q + "&admin=1",
+		q + "&id=7",
+		strings.Replace(q, "&sig=", "&x=", 1),
+	} {
+		if _, err := VerifyQuery(bad, map[string][]byte{"": key}, HS256); !errors.Is(err, ErrSignature) {
+			t.Errorf("%s: expected ErrSignature, got %v", bad, err)
+		}
+	}
+	if _, err := SignQuery(NameValues{Pair: map[string]any{"sig": 1}}, key, HS256); err == nil {
+		t.Errorf("expected an error for a sig name")
+	}
+}
+
+func otherAlg(alg Algorithm) Algorithm {
+	if alg == HS256 {
+		return HS512
+	}
+	return HS256
+}
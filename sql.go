@@ -0,0 +1,277 @@
+package namevalue
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+var (
+	bytesType = reflect.TypeOf([]byte(nil))
+	rawType   = reflect.TypeOf(sql.RawBytes(nil))
+	nullTypes = map[reflect.Type]reflect.Type{
+		reflect.TypeOf(sql.NullString{}):  reflect.TypeOf(""),
+		reflect.TypeOf(sql.NullInt64{}):   reflect.TypeOf(int64(0)),
+		reflect.TypeOf(sql.NullInt32{}):   reflect.TypeOf(int32(0)),
+		reflect.TypeOf(sql.NullInt16{}):   reflect.TypeOf(int16(0)),
+		reflect.TypeOf(sql.NullByte{}):    reflect.TypeOf(byte(0)),
+		reflect.TypeOf(sql.NullFloat64{}): reflect.TypeOf(float64(0)),
+		reflect.TypeOf(sql.NullBool{}):    reflect.TypeOf(false),
+	}
+)
+
+// ScanRow scans the current row of rows into NameValues.
+// Column names are folded to lower case and NULL values are stored as nil.
+//
+// The column types reported by the driver determine the Go type of each value.
+// DECIMAL, NUMERIC and MONEY columns are stored as shopspring.Decimal.
+func ScanRow(rows *sql.Rows) (NameValues, error) {
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return NameValues{}, err
+	}
+	return scanRow(rows, cts)
+}
+
+// ScanAll scans all remaining rows into a NameValues array and closes rows.
+func ScanAll(rows *sql.Rows) ([]NameValues, error) {
+	defer rows.Close()
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	result := []NameValues{}
+	for rows.Next() {
+		nvs, err := scanRow(rows, cts)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nvs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func scanRow(rows *sql.Rows, cts []*sql.ColumnType) (NameValues, error) {
+	vals := make([]any, len(cts))
+	dest := make([]any, len(cts))
+	for i := range vals {
+		dest[i] = &vals[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return NameValues{}, err
+	}
+	nvs := NameValues{
+		Pair:     make(map[string]any, len(cts)),
+		prepared: true,
+	}
+	for i, ct := range cts {
+		nvs.Pair[strings.ToLower(ct.Name())] = columnValue(ct, vals[i])
+	}
+	return nvs, nil
+}
+
+// columnValue converts a value returned by the driver to the native Go type of the column
+func columnValue(ct *sql.ColumnType, v any) any {
+	if v == nil {
+		return nil
+	}
+	switch strings.ToUpper(ct.DatabaseTypeName()) {
+	case "DECIMAL", "NUMERIC", "MONEY", "SMALLMONEY", "NUMBER":
+		if d, ok := toDecimalValue(v); ok {
+			return d
+		}
+	}
+	st := ct.ScanType()
+	if nt, ok := nullTypes[st]; ok {
+		st = nt
+	}
+	if b, ok := v.([]byte); ok {
+		// Drivers may reuse their buffers, so always copy
+		if st == bytesType || st == rawType {
+			return append([]byte(nil), b...)
+		}
+		v = string(b)
+	}
+	if st == nil || st.Kind() == reflect.Interface {
+		return v
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Type() == st {
+		return v
+	}
+	if s, ok := v.(string); ok {
+		// Text protocols return numbers and booleans as strings
+		var err error
+		switch {
+		case isIntKind(st.Kind()):
+			var i int64
+			i, err = strconv.ParseInt(s, 10, 64)
+			rv = reflect.ValueOf(i)
+		case isUintKind(st.Kind()):
+			var u uint64
+			u, err = strconv.ParseUint(s, 10, 64)
+			rv = reflect.ValueOf(u)
+		case st.Kind() == reflect.Float32 || st.Kind() == reflect.Float64:
+			var f float64
+			f, err = strconv.ParseFloat(s, 64)
+			rv = reflect.ValueOf(f)
+		case st.Kind() == reflect.Bool:
+			var b bool
+			b, err = strconv.ParseBool(s)
+			rv = reflect.ValueOf(b)
+		default:
+			return v
+		}
+		if err != nil {
+			return v
+		}
+	}
+	if isScalarKind(rv.Kind()) && isScalarKind(st.Kind()) && rv.CanConvert(st) {
+		return rv.Convert(st).Interface()
+	}
+	return v
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+// isScalarKind reports whether values of kind k can be converted to each other without changing their meaning
+func isScalarKind(k reflect.Kind) bool {
+	return isIntKind(k) || isUintKind(k) || k == reflect.Float32 || k == reflect.Float64 || k == reflect.Bool
+}
+
+// toDecimalValue converts a driver value to a decimal
+func toDecimalValue(v any) (ssd.Decimal, bool) {
+	switch t := v.(type) {
+	case []byte:
+		d, err := ssd.NewFromString(string(t))
+		return d, err == nil
+	case string:
+		d, err := ssd.NewFromString(t)
+		return d, err == nil
+	case int64:
+		return ssd.NewFromInt(t), true
+	case float64:
+		return ssd.NewFromFloat(t), true
+	}
+	return ssd.Decimal{}, false
+}
+
+// Scan implements the sql.Scanner interface so that name values can be read from a JSON column.
+func (nvp *NameValues) Scan(src any) error {
+	var data []byte
+	switch t := src.(type) {
+	case nil:
+		nvp.Pair = nil
+		nvp.prepared = false
+		return nil
+	case []byte:
+		data = t
+	case string:
+		data = []byte(t)
+	default:
+		return fmt.Errorf("namevalue: cannot scan %T into NameValues", src)
+	}
+	nvs, err := FromJSON(data)
+	if err != nil {
+		return err
+	}
+	nvp.Pair = nvs.Pair
+	nvp.prepared = nvs.prepared
+	return nil
+}
+
+// Value implements the driver.Valuer interface so that name values can be stored in a JSON column.
+// Nil name values are stored as NULL.
+func (nv NameValues) Value() (driver.Value, error) {
+	if nv.Pair == nil {
+		return nil, nil
+	}
+	b, err := ToJSON(nv)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface so that a name value can be read from a JSON column.
+func (nv *NameValue[T]) Scan(src any) error {
+	var data []byte
+	switch t := src.(type) {
+	case nil:
+		*nv = NameValue[T]{}
+		return nil
+	case []byte:
+		data = t
+	case string:
+		data = []byte(t)
+	default:
+		return fmt.Errorf("namevalue: cannot scan %T into NameValue", src)
+	}
+	var raw struct {
+		Name  string          `json:"name"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	res := NameValue[T]{
+		Name: raw.Name,
+	}
+	if len(raw.Value) > 0 {
+		// Untyped values are decoded precisely, typed values use their own decoding
+		if p, ok := any(&res.Value).(*any); ok {
+			v, err := decodePreciseValue(raw.Value)
+			if err != nil {
+				return err
+			}
+			*p = v
+		} else if err := json.Unmarshal(raw.Value, &res.Value); err != nil {
+			return err
+		}
+	}
+	*nv = res
+	return nil
+}
+
+// Valuer returns a driver.Valuer so that a name value can be stored in a JSON column.
+//
+// NameValue cannot implement driver.Valuer itself because its Value field
+// would clash with the Value method.
+func (nv NameValue[T]) Valuer() driver.Valuer {
+	return valuerFunc(func() (driver.Value, error) {
+		b, err := json.Marshal(struct {
+			Name  string `json:"name,omitempty"`
+			Value any    `json:"value,omitempty"`
+		}{
+			Name:  nv.Name,
+			Value: toPrecise(any(nv.Value)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	})
+}
+
+// valuerFunc adapts a function to the driver.Valuer interface
+type valuerFunc func() (driver.Value, error)
+
+// Value implements the driver.Valuer interface
+func (f valuerFunc) Value() (driver.Value, error) {
+	return f()
+}
@@ -0,0 +1,148 @@
+package namevalue
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+// fakeDriver is an in-memory driver that returns the same fixed result set for every query
+type (
+	fakeDriver struct{}
+	fakeConn   struct{}
+	fakeStmt   struct{}
+	fakeRows   struct {
+		pos int
+	}
+	fakeColumn struct {
+		name     string
+		dbType   string
+		scanType reflect.Type
+	}
+)
+
+var (
+	fakeColumns = []fakeColumn{
+		{"ID", "INTEGER", reflect.TypeOf(int32(0))},
+		{"Name", "VARCHAR", reflect.TypeOf("")},
+		{"Balance", "DECIMAL", reflect.TypeOf([]byte(nil))},
+		{"Active", "BOOLEAN", reflect.TypeOf(sql.NullBool{})},
+		{"Photo", "BLOB", reflect.TypeOf([]byte(nil))},
+	}
+	fakeData = [][]driver.Value{
+		{int64(1), []byte("Zaldy"), []byte("1024.50"), true, []byte{1, 2}},
+		{int64(2), "Razzie", nil, nil, nil},
+	}
+)
+
+func init() {
+	sql.Register("namevalue-fake", fakeDriver{})
+}
+
+func (fakeDriver) Open(string) (driver.Conn, error)         { return fakeConn{}, nil }
+func (fakeConn) Prepare(string) (driver.Stmt, error)        { return fakeStmt{}, nil }
+func (fakeConn) Close() error                               { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                  { return nil, errors.New("not supported") }
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return nil, errors.New("not supported") }
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return &fakeRows{}, nil }
+
+func (r *fakeRows) Columns() []string {
+	cols := make([]string, len(fakeColumns))
+	for i, c := range fakeColumns {
+		cols[i] = c.name
+	}
+	return cols
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(fakeData) {
+		return io.EOF
+	}
+	copy(dest, fakeData[r.pos])
+	r.pos++
+	return nil
+}
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(i int) string { return fakeColumns[i].dbType }
+func (r *fakeRows) ColumnTypeScanType(i int) reflect.Type   { return fakeColumns[i].scanType }
+
+func TestScanAll(t *testing.T) {
+	db, err := sql.Open("namevalue-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT * FROM people")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ScanAll(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(result))
+	}
+
+	first := result[0]
+	if v, _ := first.Plain("id"); v != int32(1) {
+		t.Errorf("id: expected int32 1, got %T %v", v, v)
+	}
+	if v, _ := first.String("NAME"); v != "Zaldy" {
+		t.Errorf("name: expected Zaldy, got %s", v)
+	}
+	if v, _ := first.Plain("balance"); !reflect.DeepEqual(v, ssd.RequireFromString("1024.50")) {
+		t.Errorf("balance: expected decimal 1024.50, got %T %v", v, v)
+	}
+	if v := Get[bool](first, "active"); !v {
+		t.Errorf("active: expected true")
+	}
+	if v, _ := first.Plain("photo"); !reflect.DeepEqual(v, []byte{1, 2}) {
+		t.Errorf("photo: expected bytes, got %T %v", v, v)
+	}
+
+	second := result[1]
+	if v := GetPtr[bool](second, "active"); v != nil {
+		t.Errorf("active: expected nil, got %v", *v)
+	}
+	if !second.Exists("balance") {
+		t.Errorf("balance: expected NULL column to exist")
+	}
+}
+
+func TestScanRow(t *testing.T) {
+	db, err := sql.Open("namevalue-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT * FROM people")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		nvs, err := ScanRow(rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name, _ := nvs.String("name")
+		names = append(names, name)
+	}
+	if !reflect.DeepEqual(names, []string{"Zaldy", "Razzie"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+}
@@ -0,0 +1,258 @@
+package namevalue
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the SQL dialect used to build statements
+type Dialect int
+
+// SQL dialects
+const (
+	Postgres Dialect = iota
+	MySQL
+	SQLite
+	SQLServer
+)
+
+// ErrNoValues is returned when a statement is built from empty name values
+var ErrNoValues = errors.New("namevalue: no values")
+
+// ErrNoWhere is returned when an UPDATE statement is built without a WHERE clause
+var ErrNoWhere = errors.New("namevalue: no where values")
+
+// InsertSQL builds a parameterized INSERT statement for table.
+// Columns are the sorted names of the values and the arguments line up with the placeholders.
+func InsertSQL(d Dialect, table string, values NameValues) (string, []any, error) {
+	cols := values.Keys()
+	if len(cols) == 0 {
+		return "", nil, ErrNoValues
+	}
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(d.quoteTable(table))
+	sb.WriteString(" (")
+	sb.WriteString(d.columnList(cols, ""))
+	sb.WriteString(") VALUES (")
+	sb.WriteString(d.placeholders(len(cols), 0))
+	sb.WriteString(")")
+	return sb.String(), values.args(cols), nil
+}
+
+// UpdateSQL builds a parameterized UPDATE ... SET statement for table.
+// The where name values are appended as an equality WHERE clause.
+// It returns ErrNoWhere if where is empty so that a missing filter does not update every row.
+func UpdateSQL(d Dialect, table string, values, where NameValues) (string, []any, error) {
+	cols := values.Keys()
+	if len(cols) == 0 {
+		return "", nil, ErrNoValues
+	}
+	if len(where.Keys()) == 0 {
+		return "", nil, ErrNoWhere
+	}
+	var sb strings.Builder
+	sb.WriteString("UPDATE ")
+	sb.WriteString(d.quoteTable(table))
+	sb.WriteString(" SET ")
+	for i, c := range cols {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(d.quote(c))
+		sb.WriteString(" = ")
+		sb.WriteString(d.placeholder(i + 1))
+	}
+	args := values.args(cols)
+	clause, wargs := WhereSQL(d, where, len(args))
+	sb.WriteString(" WHERE ")
+	sb.WriteString(clause)
+	return sb.String(), append(args, wargs...), nil
+}
+
+// UpsertSQL builds a parameterized statement that inserts the values, or updates them
+// when a row with the same conflict columns already exists.
+//
+// Postgres and SQLite use ON CONFLICT, MySQL uses ON DUPLICATE KEY UPDATE
+// and SQL Server uses MERGE.
+func UpsertSQL(d Dialect, table string, values NameValues, conflict []string) (string, []any, error) {
+	cols := values.Keys()
+	if len(cols) == 0 {
+		return "", nil, ErrNoValues
+	}
+	if len(conflict) == 0 {
+		return "", nil, errors.New("namevalue: no conflict columns")
+	}
+	keys := make([]string, len(conflict))
+	isKey := make(map[string]bool, len(conflict))
+	for i, c := range conflict {
+		keys[i] = strings.ToLower(c)
+		if !values.Exists(keys[i]) {
+			return "", nil, fmt.Errorf("namevalue: conflict column %q has no value", c)
+		}
+		isKey[keys[i]] = true
+	}
+	var upd []string
+	for _, c := range cols {
+		if !isKey[c] {
+			upd = append(upd, c)
+		}
+	}
+
+	var sb strings.Builder
+	if d == SQLServer {
+		sb.WriteString("MERGE INTO ")
+		sb.WriteString(d.quoteTable(table))
+		sb.WriteString(" AS target USING (VALUES (")
+		sb.WriteString(d.placeholders(len(cols), 0))
+		sb.WriteString(")) AS source (")
+		sb.WriteString(d.columnList(cols, ""))
+		sb.WriteString(") ON ")
+		for i, k := range keys {
+			if i > 0 {
+				sb.WriteString(" AND ")
+			}
+			sb.WriteString("target." + d.quote(k) + " = source." + d.quote(k))
+		}
+		if len(upd) > 0 {
+			sb.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+			for i, c := range upd {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteString(d.quote(c) + " = source." + d.quote(c))
+			}
+		}
+		sb.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+		sb.WriteString(d.columnList(cols, ""))
+		sb.WriteString(") VALUES (")
+		sb.WriteString(d.columnList(cols, "source."))
+		sb.WriteString(");")
+		return sb.String(), values.args(cols), nil
+	}
+
+	ins, args, _ := InsertSQL(d, table, values)
+	sb.WriteString(ins)
+	switch d {
+	case MySQL:
+		sb.WriteString(" ON DUPLICATE KEY UPDATE ")
+		if len(upd) == 0 {
+			// MySQL has no DO NOTHING, so assign a key to itself
+			sb.WriteString(d.quote(keys[0]) + " = " + d.quote(keys[0]))
+		}
+		for i, c := range upd {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(d.quote(c) + " = VALUES(" + d.quote(c) + ")")
+		}
+	default:
+		sb.WriteString(" ON CONFLICT (")
+		sb.WriteString(d.columnList(keys, ""))
+		sb.WriteString(")")
+		if len(upd) == 0 {
+			sb.WriteString(" DO NOTHING")
+			break
+		}
+		sb.WriteString(" DO UPDATE SET ")
+		for i, c := range upd {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(d.quote(c) + " = EXCLUDED." + d.quote(c))
+		}
+	}
+	return sb.String(), args, nil
+}
+
+// WhereSQL builds an equality WHERE clause, without the WHERE keyword, joined by AND.
+// Nil values are compared with IS NULL and take no argument.
+// The offset is the number of placeholders that precede the clause in the statement.
+//
+// This function returns an empty string if there are no values.
+func WhereSQL(d Dialect, where NameValues, offset int) (string, []any) {
+	cols := where.Keys()
+	if len(cols) == 0 {
+		return "", nil
+	}
+	var (
+		sb   strings.Builder
+		args []any
+	)
+	for i, c := range cols {
+		if i > 0 {
+			sb.WriteString(" AND ")
+		}
+		sb.WriteString(d.quote(c))
+		v, _ := where.Plain(c)
+		if v = deref(v); v == nil {
+			sb.WriteString(" IS NULL")
+			continue
+		}
+		args = append(args, v)
+		sb.WriteString(" = ")
+		sb.WriteString(d.placeholder(offset + len(args)))
+	}
+	return sb.String(), args
+}
+
+// args returns the values of the columns in order
+func (nvp *NameValues) args(cols []string) []any {
+	args := make([]any, len(cols))
+	for i, c := range cols {
+		v, _ := nvp.Plain(c)
+		args[i] = deref(v)
+	}
+	return args
+}
+
+// quote quotes an identifier
+func (d Dialect) quote(ident string) string {
+	switch d {
+	case MySQL:
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	case SQLServer:
+		return "[" + strings.ReplaceAll(ident, "]", "]]") + "]"
+	}
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// quoteTable quotes a table name that may be qualified by a schema
+func (d Dialect) quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i := range parts {
+		parts[i] = d.quote(parts[i])
+	}
+	return strings.Join(parts, ".")
+}
+
+// placeholder returns the n-th placeholder, starting at 1
+func (d Dialect) placeholder(n int) string {
+	switch d {
+	case Postgres:
+		return "$" + strconv.Itoa(n)
+	case SQLServer:
+		return "@p" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// placeholders returns a comma-separated list of count placeholders after offset
+func (d Dialect) placeholders(count, offset int) string {
+	ph := make([]string, count)
+	for i := range ph {
+		ph[i] = d.placeholder(offset + i + 1)
+	}
+	return strings.Join(ph, ", ")
+}
+
+// columnList returns a comma-separated list of quoted columns with a prefix
+func (d Dialect) columnList(cols []string, prefix string) string {
+	qc := make([]string, len(cols))
+	for i, c := range cols {
+		qc[i] = prefix + d.quote(c)
+	}
+	return strings.Join(qc, ", ")
+}
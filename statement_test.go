@@ -0,0 +1,100 @@
+package namevalue
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInsertSQL(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{"Name": "Zaldy", "age": 48, "band": "Razzie"}}
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{Postgres, `INSERT INTO "app"."people" ("age", "band", "name") VALUES ($1, $2, $3)`},
+		{MySQL, "INSERT INTO `app`.`people` (`age`, `band`, `name`) VALUES (?, ?, ?)"},
+		{SQLServer, `INSERT INTO [app].[people] ([age], [band], [name]) VALUES (@p1, @p2, @p3)`},
+	}
+	for _, tt := range tests {
+		q, args, err := InsertSQL(tt.dialect, "app.people", nvs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q != tt.expected {
+			t.Errorf("expected %s, got %s", tt.expected, q)
+		}
+		if !reflect.DeepEqual(args, []any{48, "Razzie", "Zaldy"}) {
+			t.Errorf("unexpected args %v", args)
+		}
+	}
+
+	if _, _, err := InsertSQL(Postgres, "people", NameValues{}); err != ErrNoValues {
+		t.Errorf("expected ErrNoValues, got %v", err)
+	}
+}
+
+func TestUpdateSQL(t *testing.T) {
+	values := NameValues{Pair: map[string]any{"name": "Zaldy", "age": 49}}
+	where := NameValues{Pair: map[string]any{"id": 7, "deleted_at": nil}}
+
+	q, args, err := UpdateSQL(Postgres, "people", values, where)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `UPDATE "people" SET "age" = $1, "name" = $2 WHERE "deleted_at" IS NULL AND "id" = $3`
+	if q != expected {
+		t.Errorf("expected %s, got %s", expected, q)
+	}
+	if !reflect.DeepEqual(args, []any{49, "Zaldy", 7}) {
+		t.Errorf("unexpected args %v", args)
+	}
+
+	if _, _, err := UpdateSQL(Postgres, "people", values, NameValues{}); !errors.Is(err, ErrNoWhere) {
+		t.Errorf("expected ErrNoWhere, got %v", err)
+	}
+}
+
+func TestUpsertSQL(t *testing.T) {
+	nvs := NameValues{Pair: map[string]any{"id": 7, "name": "Zaldy"}}
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{Postgres, `INSERT INTO "people" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`},
+		{SQLite, `INSERT INTO "people" ("id", "name") VALUES (?, ?) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`},
+		{MySQL, "INSERT INTO `people` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)"},
+		{SQLServer, `MERGE INTO [people] AS target USING (VALUES (@p1, @p2)) AS source ([id], [name]) ON target.[id] = source.[id] WHEN MATCHED THEN UPDATE SET [name] = source.[name] WHEN NOT MATCHED THEN INSERT ([id], [name]) VALUES (source.[id], source.[name]);`},
+	}
+	for _, tt := range tests {
+		q, args, err := UpsertSQL(tt.dialect, "people", nvs, []string{"ID"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q != tt.expected {
+			t.Errorf("expected %s, got %s", tt.expected, q)
+		}
+		if !reflect.DeepEqual(args, []any{7, "Zaldy"}) {
+			t.Errorf("unexpected args %v", args)
+		}
+	}
+
+	if _, _, err := UpsertSQL(Postgres, "people", nvs, []string{"email"}); err == nil {
+		t.Errorf("expected an error for a missing conflict column")
+	}
+}
+
+func TestWhereSQL(t *testing.T) {
+	q, args := WhereSQL(Postgres, NameValues{Pair: map[string]any{"b": 2, "a": 1}}, 3)
+	if q != `"a" = $4 AND "b" = $5` {
+		t.Errorf("unexpected clause %s", q)
+	}
+	if !reflect.DeepEqual(args, []any{1, 2}) {
+		t.Errorf("unexpected args %v", args)
+	}
+	if q, args := WhereSQL(Postgres, NameValues{}, 0); q != "" || args != nil {
+		t.Errorf("expected an empty clause")
+	}
+}
@@ -0,0 +1,123 @@
+package namevalue
+
+import (
+	"sort"
+	"strings"
+)
+
+// UnknownKey is a name that is not in the set of known names
+type UnknownKey struct {
+	Name        string
+	Suggestions []string // Known names close to Name, nearest first
+}
+
+// String returns the unknown name and what it might have meant
+func (uk UnknownKey) String() string {
+	if len(uk.Suggestions) == 0 {
+		return "unknown key " + uk.Name
+	}
+	return "unknown key " + uk.Name + ", did you mean " + strings.Join(uk.Suggestions, " or ") + "?"
+}
+
+// CheckUnknown reports the names in the name values that are not known.
+// The comparison is case-insensitive. The result is sorted by name.
+func CheckUnknown(nvs NameValues, known []string) []UnknownKey {
+	set := make(map[string]bool, len(known))
+	for _, k := range known {
+		set[strings.ToLower(k)] = true
+	}
+	var res []UnknownKey
+	for _, n := range nvs.Keys() {
+		if set[n] {
+			continue
+		}
+		res = append(res, UnknownKey{
+			Name:        n,
+			Suggestions: Suggest(n, known),
+		})
+	}
+	return res
+}
+
+// Names returns the names declared by the schema
+func (s *Schema) Names() []string {
+	names := make([]string, len(s.Fields))
+	for i := range s.Fields {
+		names[i] = s.Fields[i].Name
+	}
+	return names
+}
+
+// CheckUnknown reports the names in the name values that are not declared by the schema.
+func (s *Schema) CheckUnknown(nvs NameValues) []UnknownKey {
+	return CheckUnknown(nvs, s.Names())
+}
+
+// Suggest returns the candidates within a small edit distance of name, nearest first.
+// The allowed distance grows with the length of the name.
+func Suggest(name string, candidates []string) []string {
+	name = strings.ToLower(name)
+	limit := max(2, len([]rune(name))/3)
+	type match struct {
+		name string
+		dist int
+	}
+	var matches []match
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		if lc == name || seen[lc] {
+			continue
+		}
+		seen[lc] = true
+		if d := editDistance(name, lc); d <= limit {
+			matches = append(matches, match{lc, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].dist != matches[j].dist {
+			return matches[i].dist < matches[j].dist
+		}
+		return matches[i].name < matches[j].name
+	})
+	res := make([]string, len(matches))
+	for i := range matches {
+		res[i] = matches[i].name
+	}
+	return res
+}
+
+// Nearest returns the candidate nearest to name, or an empty string if none is close enough
+func Nearest(name string, candidates []string) string {
+	s := Suggest(name, candidates)
+	if len(s) == 0 {
+		return ""
+	}
+	return s[0]
+}
+
+// editDistance returns the Damerau-Levenshtein (optimal string alignment) distance of two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,70 @@
+package namevalue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckUnknown(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"databse_url": "postgres://localhost",
+			"Port":        5432,
+			"zzz":         true,
+		},
+	}
+	res := CheckUnknown(nvs, []string{"database_url", "port", "host"})
+	expected := []UnknownKey{
+		{Name: "databse_url", Suggestions: []string{"database_url"}},
+		{Name: "zzz", Suggestions: []string{}},
+	}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected %v, got %v", expected, res)
+	}
+	if s := res[0].String(); s != "unknown key databse_url, did you mean database_url?" {
+		t.Errorf("unexpected message %q", s)
+	}
+
+	schema := NewSchema(Field{Name: "database_url"}, Field{Name: "port"}, Field{Name: "zzz"})
+	if res := schema.CheckUnknown(nvs); len(res) != 1 || res[0].Name != "databse_url" {
+		t.Errorf("unexpected schema result %v", res)
+	}
+}
+
+func TestOnMiss(t *testing.T) {
+	var misses [][2]string
+	nvs := NameValues{
+		Pair: map[string]any{"timeout": 30},
+		OnMiss: func(name, nearest string) {
+			misses = append(misses, [2]string{name, nearest})
+		},
+	}
+	nvs.Int("timout")
+	nvs.String("color")
+	Get[int](nvs, "TIMEOUT")
+	if nvs.Exists("other") {
+		t.Errorf("unexpected existence")
+	}
+
+	expected := [][2]string{{"timout", "timeout"}, {"color", ""}}
+	if !reflect.DeepEqual(misses, expected) {
+		t.Errorf("expected %v, got %v", expected, misses)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		d    int
+	}{
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"databse", "database", 1},
+		{"ab", "ba", 1},
+	}
+	for _, tt := range tests {
+		if d := editDistance(tt.a, tt.b); d != tt.d {
+			t.Errorf("%s/%s: expected %d, got %d", tt.a, tt.b, tt.d, d)
+		}
+	}
+}
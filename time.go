@@ -0,0 +1,182 @@
+package namevalue
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	ssd "github.com/shopspring/decimal"
+)
+
+// TimeLayouts are the layouts tried when reading a time without explicit layouts
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// epochMillisThreshold is the smallest absolute epoch value treated as milliseconds.
+// It is about the year 33658 in seconds, or September 2001 in milliseconds.
+const epochMillisThreshold = 1_000_000_000_000
+
+var daysPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)([dw])`)
+
+// Time returns the name value as time.Time. The second result returns the existence.
+//
+// Strings are parsed with the layouts, or with TimeLayouts if none are given, and may be
+// enclosed in single or double quotes. Numbers and numeric strings are Unix epoch seconds,
+// or milliseconds if they are too large to be seconds. Times without a time zone are read
+// in the Location of the name values.
+func (nvp *NameValues) Time(name string, layouts ...string) (time.Time, bool) {
+	tmp, exists := nvp.lookup(name, "time.Time")
+	if !exists {
+		return time.Time{}, exists
+	}
+	val, _ := toTime(tmp, layouts, nvp.location())
+	return val, exists
+}
+
+// Times returns the values as a time array
+// If the name does not exist, this function will return an empty time array
+func (nvp *NameValues) Times(name string, layouts ...string) []time.Time {
+	loc := nvp.location()
+	return listOf(nvp, name, func(v any) (time.Time, error) {
+		return toTime(v, layouts, loc)
+	})
+}
+
+// Duration returns the name value as time.Duration. The second result returns the existence.
+//
+// Strings are parsed like time.ParseDuration, with the additional units d for days
+// and w for weeks, such as 1d2h30m. Numbers and numeric strings are seconds.
+func (nvp *NameValues) Duration(name string) (time.Duration, bool) {
+	tmp, exists := nvp.lookup(name, "time.Duration")
+	if !exists {
+		return 0, exists
+	}
+	val, _ := toDuration(tmp)
+	return val, exists
+}
+
+// PtrTime returns the name value as pointer to time.Time. The second result returns the existence.
+func (nvp *NameValues) PtrTime(name string, layouts ...string) (*time.Time, bool) {
+	value, exists := nvp.Time(name, layouts...)
+	if !exists {
+		return nil, exists
+	}
+	return &value, exists
+}
+
+// PtrDuration returns the name value as pointer to time.Duration. The second result returns the existence.
+func (nvp *NameValues) PtrDuration(name string) (*time.Duration, bool) {
+	value, exists := nvp.Duration(name)
+	if !exists {
+		return nil, exists
+	}
+	return &value, exists
+}
+
+// location returns the location for times without a time zone
+func (nvp *NameValues) location() *time.Location {
+	if nvp.Location == nil {
+		return time.UTC
+	}
+	return nvp.Location
+}
+
+// toTime converts a value to time.Time
+func toTime(v any, layouts []string, loc *time.Location) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		s := unquote(strings.TrimSpace(t))
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return fromEpoch(i, loc), nil
+		}
+		if len(layouts) == 0 {
+			layouts = TimeLayouts
+		}
+		var err error
+		for _, l := range layouts {
+			var tm time.Time
+			if tm, err = time.ParseInLocation(l, s, loc); err == nil {
+				return tm, nil
+			}
+		}
+		return time.Time{}, err
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return time.Time{}, convertError(v, KindTime)
+	}
+	return fromEpoch(i, loc), nil
+}
+
+// fromEpoch converts Unix epoch seconds or milliseconds to time
+func fromEpoch(i int64, loc *time.Location) time.Time {
+	if i >= epochMillisThreshold || i <= -epochMillisThreshold {
+		return time.UnixMilli(i).In(loc)
+	}
+	return time.Unix(i, 0).In(loc)
+}
+
+// toDuration converts a value to time.Duration
+func toDuration(v any) (time.Duration, error) {
+	switch t := v.(type) {
+	case time.Duration:
+		return t, nil
+	case string:
+		return parseDuration(unquote(strings.TrimSpace(t)))
+	case float32, float64, ssd.Decimal:
+		f, err := toFloat64(t)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(f * float64(time.Second)), nil
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, convertError(v, KindDuration)
+	}
+	return time.Duration(i) * time.Second, nil
+}
+
+// parseDuration parses a duration string that may contain days and weeks
+func parseDuration(s string) (time.Duration, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(f * float64(time.Second)), nil
+	}
+	// Days and weeks are converted to hours, which time.ParseDuration can add up
+	var perr error
+	s = daysPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sm := daysPattern.FindStringSubmatch(m)
+		n, err := strconv.ParseFloat(sm[1], 64)
+		if err != nil {
+			perr = err
+		}
+		if sm[2] == "w" {
+			n *= 7
+		}
+		return strconv.FormatFloat(n*24, 'f', -1, 64) + "h"
+	})
+	if perr != nil {
+		return 0, perr
+	}
+	return time.ParseDuration(s)
+}
+
+// unquote removes matching single or double quotes around a string
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package namevalue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNVTime(t *testing.T) {
+	manila := time.FixedZone("PHT", 8*60*60)
+	nvs := NameValues{
+		Pair: map[string]any{
+			"rfc":     "2021-10-17T08:30:00+08:00",
+			"sql":     "2021-10-17 08:30:00",
+			"quoted":  anyToStr(time.Date(2021, 10, 17, 0, 30, 0, 0, time.UTC)),
+			"epoch":   1634430600,
+			"epochms": "1634430600000",
+			"custom":  "17/10/2021",
+			"typed":   time.Date(2021, 10, 17, 0, 30, 0, 0, time.UTC),
+			"bad":     "yesterday",
+		},
+		Location: manila,
+	}
+	expected := time.Date(2021, 10, 17, 0, 30, 0, 0, time.UTC)
+
+	for _, name := range []string{"rfc", "sql", "quoted", "epoch", "epochms", "typed"} {
+		v, exists := nvs.Time(name)
+		if !exists || !v.Equal(expected) {
+			t.Errorf("%s: expected %v, got %v (%v)", name, expected, v, exists)
+		}
+	}
+	if v, _ := nvs.Time("sql"); v.Location() != manila {
+		t.Errorf("sql: expected the default location, got %v", v.Location())
+	}
+	if v, _ := nvs.Time("custom", "02/01/2006"); !v.Equal(time.Date(2021, 10, 17, 0, 0, 0, 0, manila)) {
+		t.Errorf("custom: unexpected %v", v)
+	}
+	if v, exists := nvs.Time("bad"); !exists || !v.IsZero() {
+		t.Errorf("bad: expected zero time, got %v", v)
+	}
+	if v := Get[time.Time](nvs, "rfc"); !v.Equal(expected) {
+		t.Errorf("Get: expected %v, got %v", expected, v)
+	}
+	if v := GetPtr[time.Time](nvs, "bad"); v != nil {
+		t.Errorf("GetPtr: expected nil, got %v", v)
+	}
+	if v, _ := nvs.PtrTime("unknown"); v != nil {
+		t.Errorf("unknown: expected nil")
+	}
+}
+
+func TestNVDuration(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"go":      "2h30m",
+			"days":    "1d2h30m",
+			"weeks":   "'1w'",
+			"half":    "1.5d",
+			"seconds": 90,
+			"str":     "90",
+			"typed":   time.Minute,
+		},
+	}
+	tests := map[string]time.Duration{
+		"go":      2*time.Hour + 30*time.Minute,
+		"days":    26*time.Hour + 30*time.Minute,
+		"weeks":   7 * 24 * time.Hour,
+		"half":    36 * time.Hour,
+		"seconds": 90 * time.Second,
+		"str":     90 * time.Second,
+		"typed":   time.Minute,
+	}
+	for name, expected := range tests {
+		if v, _ := nvs.Duration(name); v != expected {
+			t.Errorf("%s: expected %v, got %v", name, expected, v)
+		}
+	}
+	if v := Get[time.Duration](nvs, "days"); v != 26*time.Hour+30*time.Minute {
+		t.Errorf("Get: unexpected %v", v)
+	}
+}
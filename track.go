@@ -0,0 +1,103 @@
+package namevalue
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// Access describes how a name was read while tracking
+	Access struct {
+		Name  string
+		Count int      // Number of reads
+		Types []string // Types the name was requested as, in the order first requested
+	}
+	tracker struct {
+		mu    sync.Mutex
+		reads map[string]*Access
+	}
+)
+
+// Track enables access tracking. Every read through the getters is recorded with
+// the requested type, including reads of names that do not exist.
+//
+// Copies of the name values made after calling Track share the same records.
+func (nvp *NameValues) Track() {
+	nvp.tracker = &tracker{
+		reads: make(map[string]*Access),
+	}
+}
+
+// Accesses returns the recorded reads sorted by name. It returns nil if tracking is not enabled.
+func (nvp *NameValues) Accesses() []Access {
+	if nvp.tracker == nil {
+		return nil
+	}
+	nvp.tracker.mu.Lock()
+	defer nvp.tracker.mu.Unlock()
+	res := make([]Access, 0, len(nvp.tracker.reads))
+	for _, a := range nvp.tracker.reads {
+		res = append(res, Access{
+			Name:  a.Name,
+			Count: a.Count,
+			Types: append([]string(nil), a.Types...),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// Unused returns the sorted names that exist but were never read.
+// It returns nil if tracking is not enabled.
+func (nvp *NameValues) Unused() []string {
+	if nvp.tracker == nil {
+		return nil
+	}
+	keys := nvp.Keys()
+	nvp.tracker.mu.Lock()
+	defer nvp.tracker.mu.Unlock()
+	res := []string{}
+	// Reading a path into a nested value uses the name at its root
+	read := make(map[string]bool, len(nvp.tracker.reads))
+	for n := range nvp.tracker.reads {
+		read[n] = true
+		if i := strings.IndexAny(n, ".["); i > 0 {
+			read[n[:i]] = true
+		}
+		if segs, err := parsePointer(n); err == nil && len(segs) > 0 {
+			read[segs[0]] = true
+		}
+	}
+	for _, k := range keys {
+		if !read[k] {
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+func (t *tracker) record(name, typ string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	a, ok := t.reads[name]
+	if !ok {
+		a = &Access{Name: name}
+		t.reads[name] = a
+	}
+	a.Count++
+	for _, at := range a.Types {
+		if at == typ {
+			return
+		}
+	}
+	a.Types = append(a.Types, typ)
+}
+
+// typeName returns the name of the type parameter
+func typeName[T any]() string {
+	return fmt.Sprintf("%T", *new(T))
+}
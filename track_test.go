@@ -0,0 +1,40 @@
+package namevalue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrack(t *testing.T) {
+	nvs := NameValues{
+		Pair: map[string]any{
+			"Name":   "Zaldy",
+			"age":    "48",
+			"active": "yes",
+			"unused": 1,
+		},
+	}
+	if nvs.Unused() != nil || nvs.Accesses() != nil {
+		t.Errorf("expected nil results when tracking is disabled")
+	}
+	nvs.Track()
+
+	nvs.String("name")
+	nvs.Int("AGE")
+	nvs.PtrBool("active")
+	Get[int](nvs, "age")
+	nvs.Float64("missing")
+
+	if u := nvs.Unused(); !reflect.DeepEqual(u, []string{"unused"}) {
+		t.Errorf("expected [unused], got %v", u)
+	}
+	expected := []Access{
+		{Name: "active", Count: 1, Types: []string{"bool"}},
+		{Name: "age", Count: 2, Types: []string{"int"}},
+		{Name: "missing", Count: 1, Types: []string{"float64"}},
+		{Name: "name", Count: 1, Types: []string{"string"}},
+	}
+	if a := nvs.Accesses(); !reflect.DeepEqual(a, expected) {
+		t.Errorf("expected %v, got %v", expected, a)
+	}
+}
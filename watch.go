@@ -0,0 +1,187 @@
+package namevalue
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+type (
+	// Decoder decodes the contents of a file into name values, such as FromJSON
+	Decoder func(data []byte) (NameValues, error)
+	// WatchOptions controls how a Watcher reloads its file
+	WatchOptions struct {
+		// Validate checks new values before they replace the current ones, such as Schema.Validate
+		Validate func(NameValues) error
+		// OnError is called when a reload fails. The current values are kept.
+		OnError func(error)
+	}
+	// Watcher holds the values of a file and reloads them when the file changes.
+	// It is safe for concurrent use.
+	Watcher struct {
+		path     string
+		decode   Decoder
+		opts     WatchOptions
+		mu       sync.RWMutex
+		loading  sync.Mutex
+		values   NameValues
+		hash     [sha256.Size]byte
+		err      error
+		subs     []func(Changes)
+		stop     chan struct{}
+		done     chan struct{}
+		stopOnce sync.Once
+	}
+)
+
+// WatchFile loads a file with the decoder and polls it every interval for changes.
+// The file is read at every poll and its values are replaced only if the SHA-256 hash of
+// the contents differs and the new values decode and validate. Comparing the contents
+// detects changes that keep the size and fall within the granularity of the modification
+// time. Polling works on every file system, including network shares and mounted volumes.
+//
+// It returns an error if the interval is not positive or if the first load fails.
+// Call Close to stop polling.
+func WatchFile(path string, format Decoder, interval time.Duration, opts WatchOptions) (*Watcher, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("namevalue: invalid polling interval %s", interval)
+	}
+	w := &Watcher{
+		path:   path,
+		decode: format,
+		opts:   opts,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+	go w.poll(interval)
+	return w, nil
+}
+
+// Values returns a copy of the current values. Changes to the copy, including the
+// folding of nested names by the getters, do not affect the watcher or other readers.
+func (w *Watcher) Values() NameValues {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	res := w.values
+	res.Pair, _ = cloneValue(w.values.Pair).(map[string]any)
+	return res
+}
+
+// Subscribe registers a function that is called with the changes after every reload that
+// changes the values. Functions are called in the order they were added, by the polling
+// goroutine or by Reload, and may call Reload themselves.
+func (w *Watcher) Subscribe(fn func(Changes)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs = append(w.subs, fn)
+}
+
+// Err returns the error of the last reload, or nil if it succeeded
+func (w *Watcher) Err() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.err
+}
+
+// Close stops polling. It waits for a reload in progress to finish.
+func (w *Watcher) Close() error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+	return nil
+}
+
+// Reload reads the file now and notifies the subscribers if its values changed.
+// It returns the error of the reload, which is also returned by Err.
+func (w *Watcher) Reload() error {
+	changes, subs, err := w.load()
+	if err != nil {
+		if w.opts.OnError != nil {
+			w.opts.OnError(err)
+		}
+		return err
+	}
+	for _, fn := range subs {
+		fn(changes)
+	}
+	return nil
+}
+
+// poll reloads the file every interval until the watcher is closed
+func (w *Watcher) poll(interval time.Duration) {
+	defer close(w.done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-t.C:
+		}
+		w.Reload()
+	}
+}
+
+// load reads, decodes and validates the file and swaps in its values if they changed.
+// It returns the changes and the subscribers to notify once no lock is held.
+func (w *Watcher) load() (Changes, []func(Changes), error) {
+	w.loading.Lock()
+	defer w.loading.Unlock()
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return nil, nil, w.fail(err)
+	}
+	hash := sha256.Sum256(data)
+	w.mu.Lock()
+	if w.values.Pair != nil && hash == w.hash {
+		w.mu.Unlock()
+		return nil, nil, nil
+	}
+	// A bad edit is not read again until the contents change
+	w.hash = hash
+	w.mu.Unlock()
+
+	nvs, err := w.decode(data)
+	if err == nil && w.opts.Validate != nil {
+		err = w.opts.Validate(nvs)
+	}
+	if err != nil {
+		return nil, nil, w.fail(err)
+	}
+	if nvs.Pair == nil {
+		nvs.Pair = make(map[string]any)
+	}
+	if !nvs.prepared {
+		nvs.prepare()
+	}
+
+	w.mu.Lock()
+	old := w.values
+	w.values = nvs
+	w.err = nil
+	subs := w.subs
+	w.mu.Unlock()
+
+	if old.Pair == nil {
+		return nil, nil, nil
+	}
+	changes := Diff(old, nvs, DiffOptions{})
+	if len(changes) == 0 {
+		return nil, nil, nil
+	}
+	return changes, subs, nil
+}
+
+// fail records the error of a reload
+func (w *Watcher) fail(err error) error {
+	w.mu.Lock()
+	w.err = err
+	w.mu.Unlock()
+	return err
+}
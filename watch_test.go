@@ -0,0 +1,175 @@
+package namevalue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"port": 8080, "host": "a"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	errInvalid := errors.New("port is required")
+	w, err := WatchFile(path, FromJSON, time.Hour, WatchOptions{
+		Validate: func(nvs NameValues) error {
+			if !nvs.Exists("port") {
+				return errInvalid
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	var got Changes
+	w.Subscribe(func(c Changes) { got = c })
+
+	values := w.Values()
+	if v, _ := values.Int("port"); v != 8080 {
+		t.Errorf("expected 8080, got %d", v)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"port": 9090, "host": "a"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	values = w.Values()
+	if v, _ := values.Int("port"); v != 9090 {
+		t.Errorf("expected 9090, got %d", v)
+	}
+	if len(got) != 1 || got[0].Path != "/port" || got[0].Old != 8080 || got[0].New != 9090 {
+		t.Errorf("unexpected changes %v", got)
+	}
+
+	for _, bad := range []string{`{"port": `, `{"host": "b"}`} {
+		if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Reload(); err == nil {
+			t.Errorf("expected %s to fail", bad)
+		}
+		if w.Err() == nil {
+			t.Errorf("expected Err to report the failed reload")
+		}
+		values = w.Values()
+		if v, _ := values.Int("port"); v != 9090 {
+			t.Errorf("expected the last good values to be kept, got %d", v)
+		}
+	}
+	if !errors.Is(w.Err(), errInvalid) {
+		t.Errorf("expected the validation error, got %v", w.Err())
+	}
+}
+
+func TestWatchFilePolling(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"a": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w, err := WatchFile(path, FromJSON, 5*time.Millisecond, WatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan Changes, 1)
+	w.Subscribe(func(c Changes) { changed <- c })
+
+	if err := os.WriteFile(path, []byte(`{"a": 1, "b": 2}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-changed:
+		if len(c) != 1 || c[0].Kind != ChangeAdded || c[0].Path != "/b" {
+			t.Errorf("unexpected changes %v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the change to be detected")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("expected Close to be idempotent, got %v", err)
+	}
+
+	if _, err := WatchFile(filepath.Join(t.TempDir(), "missing.json"), FromJSON, time.Second, WatchOptions{}); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
+
+func TestWatchFileSameSizeRewrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"a": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := WatchFile(path, FromJSON, 5*time.Millisecond, WatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	changed := make(chan Changes, 1)
+	w.Subscribe(func(c Changes) {
+		// A subscriber may reload without blocking the watcher
+		if err := w.Reload(); err != nil {
+			t.Error(err)
+		}
+		changed <- c
+	})
+
+	if err := os.WriteFile(path, []byte(`{"a": 2}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, fi.ModTime(), fi.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-changed:
+		if len(c) != 1 || c[0].Path != "/a" {
+			t.Errorf("unexpected changes %v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected a rewrite with the same size and time to be detected")
+	}
+
+	if _, err := WatchFile(path, FromJSON, 0, WatchOptions{}); err == nil {
+		t.Errorf("expected an error for a zero interval")
+	}
+}
+
+func TestWatcherValuesConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"db": {"Host": "a", "Port": 5432}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w, err := WatchFile(path, FromJSON, time.Hour, WatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	done := make(chan bool)
+	for i := 0; i < 4; i++ {
+		go func() {
+			values := w.Values()
+			sub := values.Sub("db")
+			v, _ := sub.String("host")
+			done <- v == "a"
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		if !<-done {
+			t.Errorf("expected every reader to see the value")
+		}
+	}
+}